@@ -1,7 +1,45 @@
 package embind
 
-import internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+import (
+	"time"
+
+	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+)
 
 func NewConfig() internal.IEngineConfig {
 	return &internal.EngineConfig{}
 }
+
+// ErrNullPointer is returned when C++ returns a null pointer to a class and
+// the engine is configured with NewConfig().WithNullPointerErrors(true).
+var ErrNullPointer = internal.ErrNullPointer
+
+// FlushPolicy decides when the class handles that are scheduled for deletion
+// with DeleteLater are deleted, see NewConfig().WithFlushPolicy().
+type FlushPolicy = internal.FlushPolicy
+
+// FlushManually only flushes pending deletes when FlushPendingDeletes is
+// called, or when the delay function calls the flush function. This is the
+// default.
+func FlushManually() FlushPolicy {
+	return internal.FlushManually()
+}
+
+// FlushAfterCall flushes the pending deletes after every top-level call.
+func FlushAfterCall() FlushPolicy {
+	return internal.FlushAfterCall()
+}
+
+// FlushOnQueueSize flushes the pending deletes when more than size handles are
+// scheduled for deletion.
+func FlushOnQueueSize(size int) FlushPolicy {
+	return internal.FlushOnQueueSize(size)
+}
+
+// FlushOnActivityAfterInterval flushes the pending deletes on the first
+// activity after the interval passed since the first handle was scheduled for
+// deletion: when the top-level call returns or when the next handle is
+// scheduled for deletion. An engine that is not used never flushes.
+func FlushOnActivityAfterInterval(interval time.Duration) FlushPolicy {
+	return internal.FlushOnActivityAfterInterval(interval)
+}
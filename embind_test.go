@@ -2,9 +2,13 @@ package embind_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	embind_external "github.com/jerbob92/wazero-emscripten-embind"
 	"github.com/jerbob92/wazero-emscripten-embind/generator/generator"
@@ -33,7 +37,7 @@ var mod api.Module
 var wasmData []byte
 
 var _ = BeforeSuite(func() {
-	wasm, err := os.ReadFile("./testdata/wasm/tests.wasm")
+	wasm, err := os.ReadFile(testWasmPath("./testdata/wasm/tests.wasm"))
 	if err != nil {
 		Expect(err).To(BeNil())
 		return
@@ -456,6 +460,75 @@ var _ = Describe("Calling embind functions", Label("library"), func() {
 			})
 		})
 
+		Context("the return type is std optional (from version 3.1.46)", func() {
+			It("returns a pointer to the value", func() {
+				version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+				Expect(err).To(BeNil())
+				if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+					return
+				}
+
+				value := int32(21)
+				res, err := engine.CallPublicSymbol(ctx, "optional_int_return_optional_int", &value)
+				Expect(err).To(BeNil())
+				Expect(res).To(BeAssignableToTypeOf(&value))
+				Expect(*res.(*int32)).To(Equal(int32(42)))
+
+				name := "embind"
+				res, err = engine.CallPublicSymbol(ctx, "optional_string_return_optional_string", &name)
+				Expect(err).To(BeNil())
+				Expect(res).To(BeAssignableToTypeOf(&name))
+				Expect(*res.(*string)).To(Equal("Hello there embind"))
+			})
+			It("accepts values that are not pointers", func() {
+				version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+				Expect(err).To(BeNil())
+				if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+					return
+				}
+
+				res, err := engine.CallPublicSymbol(ctx, "optional_int_return_optional_int", int32(2))
+				Expect(err).To(BeNil())
+				Expect(res).To(BeAssignableToTypeOf(new(int32)))
+				Expect(*res.(*int32)).To(Equal(int32(4)))
+			})
+			It("returns nil for empty optionals", func() {
+				version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+				Expect(err).To(BeNil())
+				if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+					return
+				}
+
+				res, err := engine.CallPublicSymbol(ctx, "optional_int_return_optional_int", nil)
+				Expect(err).To(BeNil())
+				Expect(res).To(BeNil())
+
+				res, err = engine.CallPublicSymbol(ctx, "optional_string_return_optional_string", (*string)(nil))
+				Expect(err).To(BeNil())
+				Expect(res).To(BeNil())
+			})
+			It("has the optional type in the symbol", func() {
+				version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+				Expect(err).To(BeNil())
+				if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+					return
+				}
+
+				var symbol embind.ISymbol
+				symbols := engine.GetSymbols()
+				for i := range symbols {
+					if symbols[i].Symbol() == "optional_int_return_optional_int" {
+						symbol = symbols[i]
+						break
+					}
+				}
+
+				Expect(symbol).To(Not(BeNil()))
+				Expect(symbol.ReturnType().Name()).To(Equal("std::optional<int>"))
+				Expect(symbol.ReturnType().Type()).To(Equal("*int32"))
+			})
+		})
+
 		Context("the return type is std wstring", func() {
 			It("gives an error on an invalid input", func() {
 				res, err := engine.CallPublicSymbol(ctx, "std_wstring_return_std_wstring", 1)
@@ -668,6 +741,299 @@ var _ = Describe("Calling embind functions", Label("library"), func() {
 				Expect(res).To(Equal(int32(2)))
 			})
 		})
+		Context("when the context is done", func() {
+			It("does not call the function when the context is already cancelled", func() {
+				cancelledCtx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				res, err := engine.CallPublicSymbol(cancelledCtx, "busy_loop")
+				Expect(err).To(Not(BeNil()))
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+				var interruptedErr *embind_external.InterruptedError
+				Expect(errors.As(err, &interruptedErr)).To(BeTrue())
+				Expect(interruptedErr.Cause).To(BeNil())
+				Expect(res).To(BeNil())
+			})
+
+			It("interrupts the function when the runtime closes on context done", func() {
+				closeRuntime, closeEngine, closeCtx := createTestRuntime(wazero.NewRuntimeConfig().WithCloseOnContextDone(true), embind_external.NewConfig())
+				defer closeRuntime.Close(ctx)
+
+				deadlineCtx, cancel := context.WithTimeout(closeCtx, 50*time.Millisecond)
+				defer cancel()
+
+				res, err := closeEngine.CallPublicSymbol(deadlineCtx, "busy_loop")
+				Expect(err).To(Not(BeNil()))
+				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				var interruptedErr *embind_external.InterruptedError
+				Expect(errors.As(err, &interruptedErr)).To(BeTrue())
+				Expect(interruptedErr.Name).To(Equal("busy_loop"))
+				Expect(res).To(BeNil())
+			})
+		})
+	})
+})
+
+// testWasmPath returns the path of the test module, which can be overridden
+// with $EMBIND_TEST_WASM to run the specs against builds of other Emscripten
+// versions.
+func testWasmPath(defaultPath string) string {
+	if path := os.Getenv("EMBIND_TEST_WASM"); path != "" {
+		return path
+	}
+	return defaultPath
+}
+
+// wasmWithImport returns a module that exports the functions that embind
+// requires and imports the given function from env.
+func wasmWithImport(name string, params []byte) []byte {
+	section := func(id byte, content ...byte) []byte {
+		return append([]byte{id, byte(len(content))}, content...)
+	}
+	wasmString := func(value string) []byte {
+		return append([]byte{byte(len(value))}, value...)
+	}
+
+	module := []byte{0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00}
+
+	// Type 0 is () -> (), type 1 is the import.
+	types := []byte{0x02, 0x60, 0x00, 0x00, 0x60, byte(len(params))}
+	types = append(types, params...)
+	types = append(types, 0x00)
+	module = append(module, section(0x01, types...)...)
+
+	imports := []byte{0x01}
+	imports = append(imports, wasmString("env")...)
+	imports = append(imports, wasmString(name)...)
+	imports = append(imports, 0x00, 0x01)
+	module = append(module, section(0x02, imports...)...)
+
+	exportNames := []string{"free", "malloc", "__getTypeName"}
+	module = append(module, section(0x03, 0x03, 0x00, 0x00, 0x00)...)
+
+	exports := []byte{byte(len(exportNames))}
+	for i := range exportNames {
+		exports = append(exports, wasmString(exportNames[i])...)
+		exports = append(exports, 0x00, byte(i+1))
+	}
+	module = append(module, section(0x07, exports...)...)
+
+	module = append(module, section(0x0a, 0x03, 0x02, 0x00, 0x0b, 0x02, 0x00, 0x0b, 0x02, 0x00, 0x0b)...)
+
+	return module
+}
+
+func createTestRuntime(runtimeConfig wazero.RuntimeConfig, engineConfig embind.IEngineConfig) (wazero.Runtime, embind_external.Engine, context.Context) {
+	testCtx := context.Background()
+	testRuntime := wazero.NewRuntimeWithConfig(testCtx, runtimeConfig)
+
+	_, err := wasi_snapshot_preview1.Instantiate(testCtx, testRuntime)
+	Expect(err).To(BeNil())
+
+	compiledModule, err := testRuntime.CompileModule(testCtx, wasmData)
+	Expect(err).To(BeNil())
+
+	builder := testRuntime.NewHostModuleBuilder("env")
+
+	emscriptenExporter, err := emscripten.NewFunctionExporterForModule(compiledModule)
+	Expect(err).To(BeNil())
+	emscriptenExporter.ExportFunctions(builder)
+
+	testEngine := embind_external.CreateEngine(engineConfig)
+	err = testEngine.NewFunctionExporterForModule(compiledModule).ExportFunctions(builder)
+	Expect(err).To(BeNil())
+
+	_, err = builder.Instantiate(testCtx)
+	Expect(err).To(BeNil())
+
+	moduleConfig := wazero.NewModuleConfig().
+		WithStartFunctions("_initialize").
+		WithName("")
+
+	testCtx = testEngine.Attach(testCtx)
+	_, err = testRuntime.InstantiateModule(testCtx, compiledModule, moduleConfig)
+	Expect(err).To(BeNil())
+
+	return testRuntime, testEngine, testCtx
+}
+
+type observedCall struct {
+	symbol    string
+	className string
+	arguments []any
+	result    any
+	err       error
+}
+
+type observedEmvalCall struct {
+	value     any
+	method    string
+	arguments []any
+	result    any
+	err       error
+}
+
+type recordingObserver struct {
+	calls      []observedCall
+	emvalCalls []observedEmvalCall
+}
+
+func (ro *recordingObserver) OnCall(ctx context.Context, symbol, className string, arguments []any, result any, err error, duration time.Duration) {
+	ro.calls = append(ro.calls, observedCall{symbol: symbol, className: className, arguments: arguments, result: result, err: err})
+}
+
+func (ro *recordingObserver) OnEmvalCall(ctx context.Context, value any, method string, arguments []any, result any, err error, duration time.Duration) {
+	ro.emvalCalls = append(ro.emvalCalls, observedEmvalCall{value: value, method: method, arguments: arguments, result: result, err: err})
+}
+
+var _ = Describe("Observing embind calls", Label("library"), func() {
+	var observer *recordingObserver
+	var observedRuntime wazero.Runtime
+	var observedEngine embind_external.Engine
+	var observedCtx context.Context
+
+	BeforeEach(func() {
+		observer = &recordingObserver{}
+		observedRuntime, observedEngine, observedCtx = createTestRuntime(wazero.NewRuntimeConfig(), embind_external.NewConfig().WithObserver(observer))
+	})
+
+	AfterEach(func() {
+		observedRuntime.Close(observedCtx)
+	})
+
+	It("notifies the observer of function calls", func() {
+		res, err := observedEngine.CallPublicSymbol(observedCtx, "bool_return_bool", true)
+		Expect(err).To(BeNil())
+		Expect(res).To(BeTrue())
+
+		Expect(observer.calls).To(HaveLen(1))
+		Expect(observer.calls[0].symbol).To(Equal("bool_return_bool"))
+		Expect(observer.calls[0].className).To(Equal(""))
+		Expect(observer.calls[0].arguments).To(Equal([]any{true}))
+		Expect(observer.calls[0].result).To(BeTrue())
+		Expect(observer.calls[0].err).To(BeNil())
+	})
+
+	It("notifies the observer of failed calls", func() {
+		_, err := observedEngine.CallPublicSymbol(observedCtx, "bool_return_bool", "test")
+		Expect(err).To(Not(BeNil()))
+
+		Expect(observer.calls).To(HaveLen(1))
+		Expect(observer.calls[0].err).To(Not(BeNil()))
+	})
+
+	It("notifies the observer of constructor, method and static method calls", func() {
+		instance, err := observedEngine.CallPublicSymbol(observedCtx, "MyClass", int32(10), "test")
+		Expect(err).To(BeNil())
+		Expect(instance).To(BeAssignableToTypeOf(&embind.ClassBase{}))
+
+		classInstance := instance.(*embind.ClassBase)
+		defer classInstance.DeleteInstance(observedCtx, classInstance)
+
+		res, err := classInstance.CallInstanceMethod(observedCtx, classInstance, "combineY", "a ")
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal("a test"))
+
+		res, err = observedEngine.CallStaticClassMethod(observedCtx, "MyClass", "getStringFromInstance", classInstance)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal("test"))
+
+		Expect(observer.calls).To(HaveLen(3))
+		Expect(observer.calls[0].symbol).To(Equal("MyClass"))
+		Expect(observer.calls[0].className).To(Equal(""))
+		Expect(observer.calls[1].symbol).To(Equal("combineY"))
+		Expect(observer.calls[1].className).To(Equal("MyClass"))
+		Expect(observer.calls[1].result).To(Equal("a test"))
+		Expect(observer.calls[2].symbol).To(Equal("getStringFromInstance"))
+		Expect(observer.calls[2].className).To(Equal("MyClass"))
+	})
+
+	It("notifies the observer of emval calls", func() {
+		err := observedEngine.RegisterEmvalSymbol("webkitAudioContext", &webkitAudioContext{})
+		Expect(err).To(BeNil())
+
+		_, err = observedEngine.CallPublicSymbol(observedCtx, "doEmval")
+		Expect(err).To(BeNil())
+
+		methods := []string{}
+		for i := range observer.emvalCalls {
+			methods = append(methods, observer.emvalCalls[i].method)
+		}
+		Expect(methods).To(ContainElement("createOscillator"))
+	})
+})
+
+var _ = Describe("Detecting the Emscripten ABI", Label("library"), func() {
+	It("exposes the detected variants of the imports", func() {
+		variants := engine.ABIVariants()
+		Expect(variants).To(HaveKey("_embind_register_bool"))
+		Expect(variants["_embind_register_bool"]).To(BeElementOf("with size argument", "without size argument"))
+	})
+
+	It("calls emval methods through the invoker (from version 4.0.0)", func() {
+		version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+		Expect(err).To(BeNil())
+		if !isSupportedByEmscriptenVersion(version.([]any), 4, 0, 0) {
+			return
+		}
+
+		variants := engine.ABIVariants()
+		Expect(variants).To(HaveKeyWithValue("_emval_create_invoker", "invoker"))
+		Expect(variants).To(HaveKeyWithValue("_emval_invoke", "invoker"))
+		Expect(variants).To(Not(HaveKey("_emval_get_method_caller")))
+
+		observer := &recordingObserver{}
+		invokerRuntime, invokerEngine, invokerCtx := createTestRuntime(wazero.NewRuntimeConfig(), embind_external.NewConfig().WithObserver(observer))
+		defer invokerRuntime.Close(invokerCtx)
+
+		err = invokerEngine.RegisterEmvalSymbol("webkitAudioContext", &webkitAudioContext{})
+		Expect(err).To(BeNil())
+
+		// doEmval constructs the context, and calls methods with and without
+		// a return value through the invoker.
+		res, err := invokerEngine.CallPublicSymbol(invokerCtx, "doEmval")
+		Expect(err).To(BeNil())
+		Expect(res).To(ContainSubstring("All done!"))
+
+		methods := []string{}
+		for i := range observer.emvalCalls {
+			methods = append(methods, observer.emvalCalls[i].method)
+		}
+		Expect(methods).To(ContainElements("new", "createOscillator", "connect", "start"))
+	})
+
+	It("fails on imports with an unknown signature", func() {
+		testCtx := context.Background()
+		testRuntime := wazero.NewRuntime(testCtx)
+		defer testRuntime.Close(testCtx)
+
+		// _embind_register_bool has never had 3 arguments.
+		compiledModule, err := testRuntime.CompileModule(testCtx, wasmWithImport("_embind_register_bool", []byte{0x7f, 0x7f, 0x7f}))
+		Expect(err).To(BeNil())
+
+		testEngine := embind_external.CreateEngine(embind_external.NewConfig())
+		err = testEngine.NewFunctionExporterForModule(compiledModule).ExportFunctions(testRuntime.NewHostModuleBuilder("env"))
+		Expect(err).To(Not(BeNil()))
+		Expect(err).To(BeAssignableToTypeOf(embind_external.UnsupportedEmscriptenVersionError{}))
+		if err != nil {
+			Expect(err.Error()).To(ContainSubstring("unsupported Emscripten version: the module imports \"_embind_register_bool\" with signature (i32, i32, i32) -> ()"))
+		}
+	})
+
+	It("fails on unknown imports", func() {
+		testCtx := context.Background()
+		testRuntime := wazero.NewRuntime(testCtx)
+		defer testRuntime.Close(testCtx)
+
+		compiledModule, err := testRuntime.CompileModule(testCtx, wasmWithImport("_embind_register_something_new", []byte{0x7f}))
+		Expect(err).To(BeNil())
+
+		testEngine := embind_external.CreateEngine(embind_external.NewConfig())
+		err = testEngine.NewFunctionExporterForModule(compiledModule).ExportFunctions(testRuntime.NewHostModuleBuilder("env"))
+		Expect(err).To(Not(BeNil()))
+		if err != nil {
+			Expect(err.Error()).To(ContainSubstring("\"_embind_register_something_new\" with signature (i32) -> (), which is not supported"))
+		}
 	})
 })
 
@@ -900,6 +1266,104 @@ All done!
 			Expect(array).To(Equal([]any{}))
 		})
 
+		It("can pass and return user types (from version 3.1.46)", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+				return
+			}
+
+			numbers, err := engine.CallPublicSymbol(ctx, "user_type_range", int32(3))
+			Expect(err).To(BeNil())
+			Expect(numbers).To(Equal([]any{int32(0), int32(1), int32(2)}))
+
+			isArray, err := engine.CallPublicSymbol(ctx, "user_type_is_array", numbers)
+			Expect(err).To(BeNil())
+			Expect(isArray).To(BeTrue())
+
+			userTypes := engine.GetUserTypes()
+			Expect(userTypes).To(HaveLen(1))
+			Expect(userTypes[0].Name()).To(Equal("NumberList"))
+			Expect(userTypes[0].Type()).To(Equal("NumberList"))
+			Expect(userTypes[0].DeclaredType()).To(Equal("number[]"))
+		})
+
+		It("can await a Go promise in a coroutine (from version 3.1.44)", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 44) {
+				return
+			}
+
+			promise := embind_external.NewPromise()
+			res, err := engine.CallPublicSymbol(ctx, "emval_coro_increment", promise)
+			Expect(err).To(BeNil())
+			Expect(res).To(BeAssignableToTypeOf(promise))
+
+			coroPromise := res.(*embind_external.Promise)
+			Expect(coroPromise.Settled()).To(BeFalse())
+			_, err = coroPromise.Result()
+			Expect(err).To(Equal(embind_external.ErrPromisePending))
+
+			err = promise.Resolve(ctx, int32(41))
+			Expect(err).To(BeNil())
+
+			value, err := coroPromise.Result()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(42)))
+		})
+
+		It("resumes coroutines through the delay function when one is set (from version 3.1.44)", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 44) {
+				return
+			}
+
+			delayed := []func(ctx context.Context) error{}
+			err = engine.SetDelayFunction(func(fn func(ctx context.Context) error) error {
+				delayed = append(delayed, fn)
+				return nil
+			})
+			Expect(err).To(BeNil())
+			defer engine.SetDelayFunction(nil)
+
+			first := embind_external.NewPromise()
+			firstRes, err := engine.CallPublicSymbol(ctx, "emval_coro_increment", first)
+			Expect(err).To(BeNil())
+
+			second := embind_external.NewPromise()
+			secondRes, err := engine.CallPublicSymbol(ctx, "emval_coro_increment", second)
+			Expect(err).To(BeNil())
+
+			// Both resumes wait for the same delayed run.
+			Expect(first.Resolve(ctx, int32(1))).To(Succeed())
+			Expect(second.Resolve(ctx, int32(2))).To(Succeed())
+			Expect(firstRes.(*embind_external.Promise).Settled()).To(BeFalse())
+			Expect(secondRes.(*embind_external.Promise).Settled()).To(BeFalse())
+			Expect(delayed).To(HaveLen(1))
+
+			Expect(delayed[0](ctx)).To(Succeed())
+			Expect(firstRes.(*embind_external.Promise).Result()).To(Equal(int32(2)))
+			Expect(secondRes.(*embind_external.Promise).Result()).To(Equal(int32(3)))
+		})
+
+		It("resumes a coroutine when the call returns when awaiting a value (from version 3.1.44)", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 44) {
+				return
+			}
+
+			res, err := engine.CallPublicSymbol(ctx, "emval_coro_increment", int32(1))
+			Expect(err).To(BeNil())
+			Expect(res).To(BeAssignableToTypeOf(&embind_external.Promise{}))
+
+			value, err := res.(*embind_external.Promise).Result()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(2)))
+		})
+
 		It("can create an iterator on an array and loop over it (from version 3.1.47)", func() {
 			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
 			Expect(err).To(BeNil())
@@ -930,6 +1394,38 @@ All done!
 			}
 		})
 	})
+
+	When("settling promises", func() {
+		It("runs the callbacks once", func() {
+			promise := embind_external.NewPromise()
+			calls := 0
+			err := promise.Then(ctx, func(ctx context.Context, value any, err error) error {
+				calls++
+				Expect(value).To(Equal("done"))
+				Expect(err).To(BeNil())
+				return nil
+			})
+			Expect(err).To(BeNil())
+			Expect(calls).To(Equal(0))
+
+			Expect(promise.Resolve(ctx, "done")).To(BeNil())
+			Expect(promise.Reject(ctx, errors.New("too late"))).To(BeNil())
+			Expect(calls).To(Equal(1))
+
+			value, err := promise.Result()
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal("done"))
+		})
+
+		It("returns the rejection error", func() {
+			promise := embind_external.NewPromise()
+			rejection := errors.New("rejected")
+			Expect(promise.Reject(ctx, rejection)).To(BeNil())
+
+			_, err := promise.Result()
+			Expect(err).To(Equal(rejection))
+		})
+	})
 })
 
 var _ = Describe("Using embind classes", Label("library"), func() {
@@ -1187,11 +1683,643 @@ var _ = Describe("Using embind classes", Label("library"), func() {
 	})
 })
 
+var _ = Describe("Allocating memory", Label("library"), func() {
+	It("writes and reads allocated memory", func() {
+		ptr, err := engine.Alloc(ctx, 4)
+		Expect(err).To(BeNil())
+
+		Expect(engine.WriteBytes(ptr, []byte{1, 2, 3, 4})).To(Succeed())
+
+		_, err = engine.CallPublicSymbol(ctx, "fillBytes", ptr, int32(2), int32(9))
+		Expect(err).To(BeNil())
+
+		data, err := engine.ReadBytes(ptr, 4)
+		Expect(err).To(BeNil())
+		Expect(data).To(Equal([]byte{9, 9, 3, 4}))
+
+		Expect(engine.Free(ctx, ptr)).To(Succeed())
+		Expect(engine.Free(ctx, ptr)).To(Not(Succeed()))
+	})
+
+	It("passes a buffer to C++", func() {
+		buffer, err := embind_external.NewBuffer[int32](ctx, engine, 4)
+		Expect(err).To(BeNil())
+		defer buffer.Free(ctx)
+
+		values, err := buffer.Slice()
+		Expect(err).To(BeNil())
+		Expect(values).To(HaveLen(4))
+		copy(values, []int32{1, 2, 3, 4})
+
+		sum, err := engine.CallPublicSymbol(ctx, "sumInt32s", buffer.Pointer(), int32(buffer.Len()))
+		Expect(err).To(BeNil())
+		Expect(sum).To(Equal(int32(10)))
+	})
+
+	It("frees the memory when the scope is closed", func() {
+		scopeCtx, scope := embind_external.NewScope(ctx)
+
+		buffer, err := embind_external.NewBuffer[uint8](scopeCtx, engine, 16)
+		Expect(err).To(BeNil())
+
+		kept, err := engine.Alloc(scopeCtx, 8)
+		Expect(err).To(BeNil())
+		scope.KeepMemory(kept)
+
+		Expect(scope.Close(ctx)).To(Succeed())
+		Expect(buffer.Free(ctx)).To(Not(Succeed()))
+		Expect(engine.Free(ctx, kept)).To(Succeed())
+	})
+})
+
+var _ = Describe("Wrapping raw pointers", Label("library"), func() {
+	destroyedObjects := func() int32 {
+		destroyed, err := engine.CallPublicSymbol(ctx, "getRawObjectsDestroyed")
+		Expect(err).To(BeNil())
+		return destroyed.(int32)
+	}
+
+	It("wraps a pointer that is owned by C++", func() {
+		destroyed := destroyedObjects()
+		address, err := engine.CallPublicSymbol(ctx, "getStaticRawAddress")
+		Expect(err).To(BeNil())
+
+		object, err := engine.WrapPointer(ctx, "RawBase", address.(uint32), false)
+		Expect(err).To(BeNil())
+		Expect(object.Pointer()).To(Equal(address))
+
+		// The instance is downcast to its dynamic type.
+		extra, err := object.CallInstanceMethod(ctx, object, "getExtra")
+		Expect(err).To(BeNil())
+		Expect(extra).To(Equal(int32(42)))
+
+		res, err := engine.CallPublicSymbol(ctx, "getRawAddress", object)
+		Expect(err).To(BeNil())
+		Expect(res).To(Equal(address))
+
+		Expect(object.DeleteInstance(ctx, object)).To(Succeed())
+		Expect(object.Pointer()).To(Equal(uint32(0)))
+		Expect(destroyedObjects()).To(Equal(destroyed))
+	})
+
+	It("deletes an owned pointer with the last handle", func() {
+		destroyed := destroyedObjects()
+		address, err := engine.CallPublicSymbol(ctx, "newRawAddress")
+		Expect(err).To(BeNil())
+
+		object, err := engine.WrapPointer(ctx, "RawBase", address.(uint32), true)
+		Expect(err).To(BeNil())
+
+		clone, err := object.CloneInstance(ctx, object)
+		Expect(err).To(BeNil())
+
+		Expect(object.DeleteInstance(ctx, object)).To(Succeed())
+		Expect(destroyedObjects()).To(Equal(destroyed))
+
+		Expect(clone.DeleteInstance(ctx, clone)).To(Succeed())
+		Expect(destroyedObjects()).To(Equal(destroyed + 1))
+	})
+
+	It("returns nil for a null pointer", func() {
+		object, err := engine.WrapPointer(ctx, "RawBase", 0, false)
+		Expect(err).To(BeNil())
+		Expect(object).To(BeNil())
+	})
+
+	It("fails on unknown classes", func() {
+		_, err := engine.WrapPointer(ctx, "UnknownClass", 8, false)
+		Expect(err).To(MatchError("could not find class UnknownClass"))
+	})
+})
+
+var _ = Describe("Handling null pointers", Label("library"), func() {
+	It("passes nil as a null pointer", func() {
+		res, err := engine.CallPublicSymbol(ctx, "isNullObject", nil)
+		Expect(err).To(BeNil())
+		Expect(res).To(BeTrue())
+
+		var object *embind.ClassBase
+		res, err = engine.CallPublicSymbol(ctx, "isNullObject", object)
+		Expect(err).To(BeNil())
+		Expect(res).To(BeTrue())
+	})
+
+	It("passes a nil pointer as false to a bool", func() {
+		var value *int
+		res, err := engine.CallPublicSymbol(ctx, "bool_return_bool", value)
+		Expect(err).To(BeNil())
+		Expect(res).To(BeFalse())
+	})
+
+	It("returns nil for a null pointer", func() {
+		res, err := engine.CallPublicSymbol(ctx, "getNullObject")
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+
+		holder, err := engine.CallPublicSymbol(ctx, "NullableHolder")
+		Expect(err).To(BeNil())
+		defer holder.(*embind.ClassBase).DeleteInstance(ctx, holder.(*embind.ClassBase))
+
+		res, err = holder.(*embind.ClassBase).CallInstanceMethod(ctx, holder, "getObject")
+		Expect(err).To(BeNil())
+		Expect(res).To(BeNil())
+	})
+
+	When("null pointer errors are enabled", func() {
+		var nullRuntime wazero.Runtime
+		var nullEngine embind_external.Engine
+		var nullCtx context.Context
+
+		BeforeEach(func() {
+			nullRuntime, nullEngine, nullCtx = createTestRuntime(wazero.NewRuntimeConfig(), embind_external.NewConfig().WithNullPointerErrors(true))
+		})
+
+		AfterEach(func() {
+			nullRuntime.Close(nullCtx)
+		})
+
+		It("returns ErrNullPointer for a null pointer", func() {
+			_, err := nullEngine.CallPublicSymbol(nullCtx, "getNullObject")
+			Expect(err).To(MatchError(embind_external.ErrNullPointer))
+
+			holder, err := nullEngine.CallPublicSymbol(nullCtx, "NullableHolder")
+			Expect(err).To(BeNil())
+			defer holder.(*embind.ClassBase).DeleteInstance(nullCtx, holder.(*embind.ClassBase))
+
+			_, err = holder.(*embind.ClassBase).CallInstanceMethod(nullCtx, holder, "getObject")
+			Expect(err).To(MatchError(embind_external.ErrNullPointer))
+			if err != nil {
+				Expect(err.Error()).To(ContainSubstring("getObject returned a null"))
+			}
+		})
+
+		It("returns other pointers", func() {
+			object, err := nullEngine.CallPublicSymbol(nullCtx, "NullableObject", int32(5))
+			Expect(err).To(BeNil())
+			defer object.(*embind.ClassBase).DeleteInstance(nullCtx, object.(*embind.ClassBase))
+
+			holder, err := nullEngine.CallPublicSymbol(nullCtx, "NullableHolder")
+			Expect(err).To(BeNil())
+			defer holder.(*embind.ClassBase).DeleteInstance(nullCtx, holder.(*embind.ClassBase))
+
+			_, err = holder.(*embind.ClassBase).CallInstanceMethod(nullCtx, holder, "setObject", object)
+			Expect(err).To(BeNil())
+
+			res, err := holder.(*embind.ClassBase).CallInstanceMethod(nullCtx, holder, "getObject")
+			Expect(err).To(BeNil())
+			Expect(res).To(Not(BeNil()))
+
+			// The returned handle owns the same raw pointer as object, so
+			// only object is deleted.
+			value, err := res.(*embind.ClassBase).CallInstanceMethod(nullCtx, res, "getValue")
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(5)))
+		})
+	})
+})
+
+var _ = Describe("Sharing smart pointers", Label("library"), func() {
+	destroyedObjects := func() int32 {
+		destroyed, err := engine.CallPublicSymbol(ctx, "getSharedObjectsDestroyed")
+		Expect(err).To(BeNil())
+		return destroyed.(int32)
+	}
+
+	newSharedObject := func(value int32) *embind.ClassBase {
+		object, err := engine.CallPublicSymbol(ctx, "SharedObject", value)
+		Expect(err).To(BeNil())
+		Expect(object).To(BeAssignableToTypeOf(&embind.ClassBase{}))
+		return object.(*embind.ClassBase)
+	}
+
+	When("the sharing policy is BY_EMVAL", func() {
+		It("shares a raw pointer with a std::shared_ptr", func() {
+			destroyed := destroyedObjects()
+			object := newSharedObject(5)
+
+			value, err := engine.CallPublicSymbol(ctx, "storeSharedPtr", object)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(5)))
+
+			// C++ keeps the object alive after the Go handle is deleted.
+			err = object.DeleteInstance(ctx, object)
+			Expect(err).To(BeNil())
+			Expect(destroyedObjects()).To(Equal(destroyed))
+
+			_, err = engine.CallPublicSymbol(ctx, "releaseSharedObjects")
+			Expect(err).To(BeNil())
+			Expect(destroyedObjects()).To(Equal(destroyed + 1))
+		})
+
+		It("fails to share a raw pointer with a smart pointer that is returned by value", func() {
+			destroyed := destroyedObjects()
+			object := newSharedObject(6)
+
+			_, err := engine.CallPublicSymbol(ctx, "storeValuePtr", object)
+			Expect(err).To(MatchError(embind_external.ErrUnknownSmartPointerSize))
+
+			// The clone of the handle that would have been shared is
+			// deleted, only the Go handle keeps the object alive.
+			Expect(object.IsInstanceDeleted(ctx, object)).To(BeFalse())
+			Expect(destroyedObjects()).To(Equal(destroyed))
+
+			err = object.DeleteInstance(ctx, object)
+			Expect(err).To(BeNil())
+			Expect(destroyedObjects()).To(Equal(destroyed + 1))
+		})
+	})
+
+	When("the sharing policy is INTRUSIVE", func() {
+		It("shares a raw pointer", func() {
+			object, err := engine.CallPublicSymbol(ctx, "getIntrusiveObject")
+			Expect(err).To(BeNil())
+
+			value, err := engine.CallPublicSymbol(ctx, "refPtrValue", object)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(42)))
+
+			// The smart pointers that were made for the call are released.
+			refcount, err := engine.CallPublicSymbol(ctx, "getIntrusiveObjectRefcount")
+			Expect(err).To(BeNil())
+			Expect(refcount).To(Equal(int32(1)))
+		})
+	})
+
+	When("the instance is held by a std::shared_ptr", func() {
+		It("takes part in the reference counting", func() {
+			destroyed := destroyedObjects()
+			object, err := engine.CallPublicSymbol(ctx, "makeSharedObject", int32(8))
+			Expect(err).To(BeNil())
+
+			shared, err := embind_external.NewShared(ctx, object.(*embind.ClassBase))
+			Expect(err).To(BeNil())
+			Expect(shared.UseCount(ctx)).To(Equal(int32(1)))
+
+			_, err = engine.CallPublicSymbol(ctx, "storeSharedPtr", shared.Get())
+			Expect(err).To(BeNil())
+			Expect(shared.UseCount(ctx)).To(Equal(int32(2)))
+
+			retained, err := shared.Retain(ctx)
+			Expect(err).To(BeNil())
+			Expect(shared.UseCount(ctx)).To(Equal(int32(3)))
+			Expect(engine.CallPublicSymbol(ctx, "getStoredSharedPtrUseCount")).To(Equal(int32(3)))
+
+			weak, err := shared.Weak(ctx)
+			Expect(err).To(BeNil())
+
+			_, err = engine.CallPublicSymbol(ctx, "releaseSharedObjects")
+			Expect(err).To(BeNil())
+			Expect(shared.Release(ctx)).To(Succeed())
+			Expect(retained.UseCount(ctx)).To(Equal(int32(1)))
+			Expect(destroyedObjects()).To(Equal(destroyed))
+
+			locked, err := weak.Lock(ctx)
+			Expect(err).To(BeNil())
+			Expect(locked).To(Not(BeNil()))
+			Expect(locked.UseCount(ctx)).To(Equal(int32(2)))
+			Expect(locked.Release(ctx)).To(Succeed())
+
+			Expect(retained.Release(ctx)).To(Succeed())
+			Expect(destroyedObjects()).To(Equal(destroyed + 1))
+
+			Expect(weak.Expired(ctx)).To(BeTrue())
+			locked, err = weak.Lock(ctx)
+			Expect(err).To(BeNil())
+			Expect(locked).To(BeNil())
+			Expect(weak.Release(ctx)).To(Succeed())
+		})
+
+		It("does not share instances that hold a raw pointer", func() {
+			object := newSharedObject(9)
+			defer object.DeleteInstance(ctx, object)
+
+			_, err := embind_external.NewShared(ctx, object)
+			Expect(err).To(MatchError(embind_external.ErrNotSharedPointer))
+		})
+
+		It("does not share a std::shared_ptr with another layout than libc++", func() {
+			res, err := engine.CallPublicSymbol(ctx, "makeSwappedSharedObject", int32(3))
+			Expect(err).To(BeNil())
+
+			object := res.(*embind.ClassBase)
+			defer object.DeleteInstance(ctx, object)
+
+			_, err = embind_external.NewShared(ctx, object)
+			Expect(err).To(MatchError(embind_external.ErrUnsupportedSharedPointerLayout))
+			if err != nil {
+				Expect(err.Error()).To(ContainSubstring("SwappedObjectSharedPtr does not start with the element pointer"))
+			}
+
+			// The instance is left alone.
+			Expect(object.IsInstanceDeleted(ctx, object)).To(BeFalse())
+			value, err := object.CallInstanceMethod(ctx, object, "getValue")
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(3)))
+		})
+	})
+
+	When("the sharing policy is NONE", func() {
+		It("does not share a raw pointer", func() {
+			object := newSharedObject(7)
+			defer object.DeleteInstance(ctx, object)
+
+			_, err := engine.CallPublicSymbol(ctx, "unsharedPtrValue", object)
+			Expect(err).To(Not(BeNil()))
+			if err != nil {
+				Expect(err.Error()).To(ContainSubstring("passing raw pointer to smart pointer SharedObjectUnsharedPtr is illegal, its sharing policy is NONE"))
+			}
+		})
+	})
+})
+
 var _ = Describe("Using the generator", Label("generator"), func() {
 	When("generating the code", func() {
 		It("succeeds generating the code", func() {
-			err := generator.Generate("./tests/generated", "./tests/generated/generate.go", wasmData, "_initialize")
+			options := generator.DefaultOptions()
+			options.OutDir = "./tests/generated"
+			options.GoFile = "./tests/generated/generate.go"
+
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+		})
+
+		It("applies the package, prefixes and filters", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.FilePrefix = "embind_"
+			options.ClassPrefix = "Cpp"
+			options.FunctionPrefix = "Embind"
+			options.Include = []string{"MyClass", "bool_*"}
+			options.Exclude = []string{"bool_return_false"}
+
+			options.OutDir = dir
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+
+			functions, err := os.ReadFile(filepath.Join(dir, "embind_functions.go"))
+			Expect(err).To(BeNil())
+			Expect(string(functions)).To(HavePrefix("// Code generated by wazero-emscripten-embind, DO NOT EDIT.\npackage bindings"))
+			Expect(string(functions)).To(ContainSubstring("func EmbindBool_return_true("))
+			Expect(string(functions)).To(Not(ContainSubstring("Bool_return_false(")))
+
+			classes, err := os.ReadFile(filepath.Join(dir, "embind_classes.go"))
+			Expect(err).To(BeNil())
+			Expect(string(classes)).To(ContainSubstring("type CppMyClass struct"))
+			Expect(string(classes)).To(Not(ContainSubstring("type CppC struct")))
+
+			engine, err := os.ReadFile(filepath.Join(dir, "embind_engine.go"))
+			Expect(err).To(BeNil())
+			Expect(string(engine)).To(ContainSubstring("func EmbindAttach(e embind.Engine) error"))
+
+			_, err = os.Stat(filepath.Join(dir, "embind_constants.go"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("generates idiomatic names", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.IdiomaticNames = true
+			options.Include = []string{"overloaded_function", "bool_return_*", "Enum"}
+			options.Renames = map[string]string{
+				"bool_return_bool": "PassBool",
+				"Enum.TWO":         "Second",
+			}
+
+			options.OutDir = dir
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+
+			functions, err := os.ReadFile(filepath.Join(dir, "functions.go"))
+			Expect(err).To(BeNil())
+			Expect(string(functions)).To(ContainSubstring("func BoolReturnTrue("))
+			Expect(string(functions)).To(ContainSubstring("func PassBool("))
+			Expect(string(functions)).To(ContainSubstring("func OverloadedFunctionInt("))
+			Expect(string(functions)).To(ContainSubstring("func OverloadedFunctionIntInt("))
+
+			enums, err := os.ReadFile(filepath.Join(dir, "enums.go"))
+			Expect(err).To(BeNil())
+			Expect(string(enums)).To(ContainSubstring("EnumEnumOne EnumEnum = 0"))
+			Expect(string(enums)).To(ContainSubstring("EnumEnumSecond EnumEnum = 1"))
+		})
+
+		It("generates typed class arguments", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.TypedClassArguments = true
+			options.Include = []string{"embind_test_get_class_name_via_base_ptr", "embind_test_accept_small_class_instance", "Base", "Derived", "SmallClass"}
+
+			options.OutDir = dir
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+
+			functions, err := os.ReadFile(filepath.Join(dir, "functions.go"))
+			Expect(err).To(BeNil())
+			Expect(string(functions)).To(ContainSubstring("func Embind_test_get_class_name_via_base_ptr(e embind.Engine, ctx context.Context, arg0 ClassBaseOrDerived) (string, error)"))
+			Expect(string(functions)).To(ContainSubstring("func Embind_test_accept_small_class_instance(e embind.Engine, ctx context.Context, arg0 *ClassSmallClass) (int32, error)"))
+
+			classes, err := os.ReadFile(filepath.Join(dir, "classes.go"))
+			Expect(err).To(BeNil())
+			Expect(string(classes)).To(ContainSubstring("type ClassBaseOrDerived interface"))
+			Expect(string(classes)).To(ContainSubstring("func (class *ClassBase) isClassBase() {}"))
+			Expect(string(classes)).To(ContainSubstring("func (class *ClassDerived) isClassBase() {}"))
+			Expect(string(classes)).To(Not(ContainSubstring("type ClassSmallClassOrDerived interface")))
+		})
+
+		It("generates interfaces and fakes", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.Fakes = true
+			options.Include = []string{"bool_return_bool", "SmallClass"}
+
+			options.OutDir = dir
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+
+			interfaces, err := os.ReadFile(filepath.Join(dir, "interfaces.go"))
+			Expect(err).To(BeNil())
+			Expect(string(interfaces)).To(ContainSubstring("type IFunctions interface"))
+			Expect(string(interfaces)).To(ContainSubstring("Bool_return_bool(ctx context.Context, arg0 bool) (bool, error)"))
+			Expect(string(interfaces)).To(ContainSubstring("type IClassSmallClass interface"))
+			Expect(string(interfaces)).To(ContainSubstring("var _ IClassSmallClass = (*ClassSmallClass)(nil)"))
+
+			fakes, err := os.ReadFile(filepath.Join(dir, "fakes.go"))
+			Expect(err).To(BeNil())
+			Expect(string(fakes)).To(ContainSubstring("type FakeFunctions struct"))
+			Expect(string(fakes)).To(ContainSubstring("var _ IClassSmallClass = (*FakeClassSmallClass)(nil)"))
+		})
+
+		It("generates implementations for classes that allow subclassing", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.Include = []string{"Interface", "InterfaceWrapper"}
+
+			options.OutDir = dir
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(BeNil())
+
+			classes, err := os.ReadFile(filepath.Join(dir, "classes.go"))
+			Expect(err).To(BeNil())
+			Expect(string(classes)).To(ContainSubstring("type ClassInterfaceImplementation interface"))
+			Expect(string(classes)).To(ContainSubstring("Invoke(ctx context.Context, arg0 string) error"))
+			Expect(string(classes)).To(ContainSubstring("func NewClassInterfaceImplementation(e embind.Engine, ctx context.Context, implementation ClassInterfaceImplementation) (*ClassInterfaceWrapper, error)"))
+			Expect(string(classes)).To(Not(ContainSubstring("type ClassInterfaceWrapperImplementation interface")))
+		})
+
+		It("generates from an instantiated engine", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.OutDir = dir
+			options.PackageName = "bindings"
+			options.Include = []string{"bool_return_true"}
+
+			err := generator.GenerateFromEngine(engine, options)
+			Expect(err).To(BeNil())
+
+			functions, err := os.ReadFile(filepath.Join(dir, "functions.go"))
+			Expect(err).To(BeNil())
+			Expect(string(functions)).To(ContainSubstring("func Bool_return_true("))
+		})
+
+		It("describes the bindings as JSON and TypeScript", func() {
+			dir := GinkgoT().TempDir()
+			options := generator.DefaultOptions()
+			options.OutDir = dir
+			options.TypeScript = true
+
+			err := generator.WriteDescription(engine, options)
+			Expect(err).To(BeNil())
+
+			jsonData, err := os.ReadFile(filepath.Join(dir, "bindings.json"))
+			Expect(err).To(BeNil())
+
+			description := &generator.Description{}
+			err = json.Unmarshal(jsonData, description)
+			Expect(err).To(BeNil())
+			Expect(description.Functions).To(ContainElement(generator.FunctionDescription{
+				Name:          "bool_return_true",
+				ReturnType:    &generator.TypeDescription{Name: "bool", GoType: "bool"},
+				ArgumentTypes: []generator.TypeDescription{},
+			}))
+
+			typeScript, err := os.ReadFile(filepath.Join(dir, "bindings.d.ts"))
+			Expect(err).To(BeNil())
+			Expect(string(typeScript)).To(ContainSubstring("  bool_return_true(): boolean;"))
+			Expect(string(typeScript)).To(ContainSubstring("export type MainModule = EmbindModule;"))
+
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+				Expect(description.UserTypes).To(ContainElement(generator.UserTypeDescription{
+					Name:         "NumberList",
+					GoType:       "NumberList",
+					DeclaredType: "number[]",
+				}))
+				Expect(string(typeScript)).To(ContainSubstring("export type NumberList = number[];"))
+				Expect(string(typeScript)).To(ContainSubstring("  user_type_range(arg0: number): number[];"))
+			}
+		})
+
+		It("reports the differences between descriptions", func() {
+			intType := generator.TypeDescription{Name: "int", GoType: "int32"}
+			floatType := generator.TypeDescription{Name: "float", GoType: "float32"}
+
+			from := &generator.Description{
+				Functions: []generator.FunctionDescription{
+					{Name: "removed", ArgumentTypes: []generator.TypeDescription{}},
+					{Name: "changed", ArgumentTypes: []generator.TypeDescription{intType}},
+				},
+				Classes: []generator.ClassDescription{
+					{Name: "Foo", Properties: []generator.PropertyDescription{
+						{Name: "getter", GetterType: &intType, SetterType: &intType},
+						{Name: "readOnly", GetterType: &intType, ReadOnly: true},
+						{Name: "setter", GetterType: &intType, SetterType: &intType},
+						{Name: "writable", GetterType: &intType, SetterType: &intType},
+						{Name: "x", GetterType: &intType, SetterType: &intType},
+					}},
+				},
+			}
+			to := &generator.Description{
+				Functions: []generator.FunctionDescription{
+					{Name: "changed", ArgumentTypes: []generator.TypeDescription{floatType}},
+					{Name: "added", ArgumentTypes: []generator.TypeDescription{}},
+				},
+				Classes: []generator.ClassDescription{
+					{Name: "Foo", Properties: []generator.PropertyDescription{
+						{Name: "getter", GetterType: &floatType, SetterType: &intType},
+						{Name: "readOnly", GetterType: &intType, SetterType: &intType},
+						{Name: "setter", GetterType: &intType, SetterType: &floatType},
+						{Name: "writable", GetterType: &intType, ReadOnly: true},
+						{Name: "x", GetterType: &intType, SetterType: &intType},
+					}},
+				},
+				Constants: []generator.ConstantDescription{
+					{Name: "NEW_CONSTANT", Type: intType, Value: 1},
+				},
+			}
+
+			diff := generator.Diff(from, to)
+			Expect(diff.HasBreakingChanges()).To(BeTrue())
+			Expect(diff.Changes).To(Equal([]generator.Change{
+				{Kind: generator.ChangeChanged, Breaking: true, Element: "function", Name: "changed/1", Old: "changed(int) void", New: "changed(float) void"},
+				{Kind: generator.ChangeRemoved, Breaking: true, Element: "function", Name: "removed/0", Old: "removed() void"},
+				{Kind: generator.ChangeAdded, Element: "function", Name: "added/0", New: "added() void"},
+				{Kind: generator.ChangeChanged, Breaking: true, Element: "property", Name: "Foo.getter", Old: "int", New: "float (set int)"},
+				{Kind: generator.ChangeChanged, Element: "property", Name: "Foo.readOnly", Old: "readonly int", New: "int"},
+				{Kind: generator.ChangeChanged, Breaking: true, Element: "property", Name: "Foo.setter", Old: "int", New: "int (set float)"},
+				{Kind: generator.ChangeChanged, Breaking: true, Element: "property", Name: "Foo.writable", Old: "int", New: "readonly int"},
+				{Kind: generator.ChangeAdded, Element: "constant", Name: "NEW_CONSTANT", New: "int = 1"},
+			}))
+
+			Expect(generator.Diff(to, to).Changes).To(BeEmpty())
+		})
+
+		It("reads the package name from the Go file", func() {
+			dir := GinkgoT().TempDir()
+			goFile := filepath.Join(dir, "generate.go")
+			err := os.WriteFile(goFile, []byte("package fromfile\n"), 0644)
+			Expect(err).To(BeNil())
+
+			options := generator.DefaultOptions()
+			options.OutDir = dir
+			options.GoFile = goFile
+			options.Include = []string{"bool_return_true"}
+
+			err = generator.GenerateFromEngine(engine, options)
+			Expect(err).To(BeNil())
+
+			functions, err := os.ReadFile(filepath.Join(dir, "functions.go"))
 			Expect(err).To(BeNil())
+			Expect(string(functions)).To(ContainSubstring("package fromfile"))
+		})
+
+		It("errors when no package name can be resolved", func() {
+			options := generator.DefaultOptions()
+			options.OutDir = GinkgoT().TempDir()
+
+			err := generator.GenerateFromEngine(engine, options)
+			Expect(err).To(Not(BeNil()))
+		})
+
+		It("errors when idiomatic names collide", func() {
+			options := generator.DefaultOptions()
+			options.PackageName = "bindings"
+			options.IdiomaticNames = true
+			options.Include = []string{"bool_return_true", "bool_return_false"}
+			options.Renames = map[string]string{
+				"bool_return_false": "BoolReturnTrue",
+			}
+
+			options.OutDir = GinkgoT().TempDir()
+			err := generator.Generate(wasmData, options)
+			Expect(err).To(Not(BeNil()))
+			if err != nil {
+				Expect(err.Error()).To(ContainSubstring("the functions bool_return_false, bool_return_true all result in the Go name BoolReturnTrue"))
+			}
 		})
 	})
 })
@@ -0,0 +1,25 @@
+// Package embindtest contains helpers to test code that uses embind.
+package embindtest
+
+import (
+	"testing"
+
+	"github.com/jerbob92/wazero-emscripten-embind"
+)
+
+// ExpectNoLeaks runs fn and fails the test when class handles or emval
+// handles that were created in fn are still alive after it returned.
+// Handles that were alive before fn was called are ignored. Configure the
+// engine with WithCaptureHandleStacks(true) to include the creation stack of
+// the leaked handles in the failure message.
+func ExpectNoLeaks(t testing.TB, engine embind.Engine, fn func()) {
+	t.Helper()
+
+	before := engine.LeakReport()
+	fn()
+	leaked := engine.LeakReport().Diff(before)
+
+	if !leaked.Empty() {
+		t.Errorf("embind handles leaked: %s", leaked)
+	}
+}
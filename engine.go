@@ -9,6 +9,11 @@ import (
 type Engine interface {
 	internal.IEngine
 	NewFunctionExporterForModule(guest wazero.CompiledModule) FunctionExporter
+
+	// ABIVariants returns the variants of the imports that changed between
+	// Emscripten versions, as detected by the last call to ExportFunctions,
+	// keyed by the name of the import.
+	ABIVariants() map[string]string
 }
 
 type DelayFunction internal.DelayFunction
@@ -21,3 +26,11 @@ func CreateEngine(config internal.IEngineConfig) Engine {
 		IEngine: internal.CreateEngine(config),
 	}
 }
+
+// InterruptedError is returned by calls that were not made or that were
+// interrupted because the context was done, it wraps the error of the
+// context.
+type InterruptedError = internal.InterruptedError
+
+// LeakReport contains the live class handles and emval handles of an engine.
+type LeakReport = internal.LeakReport
@@ -1,6 +1,11 @@
 // Code generated by wazero-emscripten-embind, DO NOT EDIT.
 package generated
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type EnumNewStyle int32
 
 const (
@@ -19,6 +24,71 @@ func (enum EnumNewStyle) Type() any {
 	return EnumNewStyle(0)
 }
 
+// String returns the Embind name of the enum value.
+func (enum EnumNewStyle) String() string {
+	switch enum {
+	case EnumNewStyle_ONE:
+		return "ONE"
+	case EnumNewStyle_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumNewStyle(%d)", int32(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumNewStyle) IsValid() bool {
+	switch enum {
+	case EnumNewStyle_ONE:
+		return true
+	case EnumNewStyle_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumNewStyle returns the enum value with the given Embind name.
+func ParseEnumNewStyle(name string) (EnumNewStyle, error) {
+	switch name {
+	case "ONE":
+		return EnumNewStyle_ONE, nil
+	case "TWO":
+		return EnumNewStyle_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumNewStyle", name)
+}
+
+func (enum EnumNewStyle) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumNewStyle", int32(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumNewStyle) UnmarshalText(text []byte) error {
+	value, err := ParseEnumNewStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumNewStyle) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumNewStyle) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
+
 type EnumOldStyle uint32
 
 const (
@@ -36,3 +106,68 @@ func (enum EnumOldStyle) Values() map[string]any {
 func (enum EnumOldStyle) Type() any {
 	return EnumOldStyle(0)
 }
+
+// String returns the Embind name of the enum value.
+func (enum EnumOldStyle) String() string {
+	switch enum {
+	case EnumOldStyle_ONE:
+		return "ONE"
+	case EnumOldStyle_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumOldStyle(%d)", uint32(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumOldStyle) IsValid() bool {
+	switch enum {
+	case EnumOldStyle_ONE:
+		return true
+	case EnumOldStyle_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumOldStyle returns the enum value with the given Embind name.
+func ParseEnumOldStyle(name string) (EnumOldStyle, error) {
+	switch name {
+	case "ONE":
+		return EnumOldStyle_ONE, nil
+	case "TWO":
+		return EnumOldStyle_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumOldStyle", name)
+}
+
+func (enum EnumOldStyle) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumOldStyle", uint32(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumOldStyle) UnmarshalText(text []byte) error {
+	value, err := ParseEnumOldStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumOldStyle) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumOldStyle) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
@@ -0,0 +1,355 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/jerbob92/wazero-emscripten-embind"
+)
+
+// Description is a machine-readable description of all the bindings that
+// a module registers with Embind.
+type Description struct {
+	Functions []FunctionDescription `json:"functions"`
+	Classes   []ClassDescription    `json:"classes"`
+	Enums     []EnumDescription     `json:"enums"`
+	Constants []ConstantDescription `json:"constants"`
+	UserTypes []UserTypeDescription `json:"userTypes"`
+}
+
+// TypeDescription describes a type by its Embind name and the Go type it is
+// mapped to. DeclaredType is the TypeScript type that user types are
+// registered with.
+type TypeDescription struct {
+	Name         string `json:"name"`
+	GoType       string `json:"goType"`
+	IsClass      bool   `json:"isClass,omitempty"`
+	IsEnum       bool   `json:"isEnum,omitempty"`
+	DeclaredType string `json:"declaredType,omitempty"`
+}
+
+// FunctionDescription describes a function, method or static method. Every
+// overload is described separately.
+type FunctionDescription struct {
+	Name          string            `json:"name"`
+	ReturnType    *TypeDescription  `json:"returnType,omitempty"`
+	ArgumentTypes []TypeDescription `json:"argumentTypes"`
+}
+
+type ConstructorDescription struct {
+	ArgumentTypes []TypeDescription `json:"argumentTypes"`
+}
+
+type PropertyDescription struct {
+	Name       string           `json:"name"`
+	GetterType *TypeDescription `json:"getterType,omitempty"`
+	SetterType *TypeDescription `json:"setterType,omitempty"`
+	ReadOnly   bool             `json:"readOnly,omitempty"`
+}
+
+type ClassDescription struct {
+	Name             string                   `json:"name"`
+	BaseClass        string                   `json:"baseClass,omitempty"`
+	Constructors     []ConstructorDescription `json:"constructors"`
+	Methods          []FunctionDescription    `json:"methods"`
+	StaticMethods    []FunctionDescription    `json:"staticMethods"`
+	Properties       []PropertyDescription    `json:"properties"`
+	StaticProperties []PropertyDescription    `json:"staticProperties"`
+}
+
+type EnumValueDescription struct {
+	Name  string `json:"name"`
+	Value any    `json:"value"`
+}
+
+type EnumDescription struct {
+	Name   string                 `json:"name"`
+	Type   TypeDescription        `json:"type"`
+	Values []EnumValueDescription `json:"values"`
+}
+
+type ConstantDescription struct {
+	Name  string          `json:"name"`
+	Type  TypeDescription `json:"type"`
+	Value any             `json:"value"`
+}
+
+// UserTypeDescription describes a type that is declared with
+// EMSCRIPTEN_DECLARE_VAL_TYPE and registered with the TypeScript type in
+// DeclaredType.
+type UserTypeDescription struct {
+	Name         string `json:"name"`
+	GoType       string `json:"goType"`
+	DeclaredType string `json:"declaredType"`
+}
+
+// exposedType contains the methods of the types that the engine exposes.
+type exposedType interface {
+	Name() string
+	Type() string
+	IsClass() bool
+	IsEnum() bool
+	DeclaredType() string
+}
+
+func describeType(t exposedType) TypeDescription {
+	return TypeDescription{
+		Name:    t.Name(),
+		GoType:  t.Type(),
+		IsClass: t.IsClass(),
+		IsEnum:  t.IsEnum(),
+
+		DeclaredType: t.DeclaredType(),
+	}
+}
+
+func describeOptionalType(t exposedType) *TypeDescription {
+	if t == nil {
+		return nil
+	}
+	description := describeType(t)
+	return &description
+}
+
+func describeTypes[T exposedType](types []T) []TypeDescription {
+	descriptions := make([]TypeDescription, len(types))
+	for i := range types {
+		descriptions[i] = describeType(types[i])
+	}
+	return descriptions
+}
+
+func sortFunctions(functions []FunctionDescription) {
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Name != functions[j].Name {
+			return functions[i].Name < functions[j].Name
+		}
+		return len(functions[i].ArgumentTypes) < len(functions[j].ArgumentTypes)
+	})
+}
+
+func sortProperties(properties []PropertyDescription) {
+	sort.Slice(properties, func(i, j int) bool {
+		return properties[i].Name < properties[j].Name
+	})
+}
+
+// Describe returns the description of the bindings that are registered in
+// the given engine.
+func Describe(engine embind.Engine) *Description {
+	description := &Description{
+		Functions: []FunctionDescription{},
+		Classes:   []ClassDescription{},
+		Enums:     []EnumDescription{},
+		Constants: []ConstantDescription{},
+		UserTypes: []UserTypeDescription{},
+	}
+
+	symbols := engine.GetSymbols()
+	for i := range symbols {
+		description.Functions = append(description.Functions, FunctionDescription{
+			Name:          symbols[i].Symbol(),
+			ReturnType:    describeOptionalType(symbols[i].ReturnType()),
+			ArgumentTypes: describeTypes(symbols[i].ArgumentTypes()),
+		})
+	}
+	sortFunctions(description.Functions)
+
+	classes := engine.GetClasses()
+	for i := range classes {
+		class := ClassDescription{
+			Name:             classes[i].Name(),
+			Constructors:     []ConstructorDescription{},
+			Methods:          []FunctionDescription{},
+			StaticMethods:    []FunctionDescription{},
+			Properties:       []PropertyDescription{},
+			StaticProperties: []PropertyDescription{},
+		}
+
+		if baseClass := classes[i].BaseClass(); baseClass != nil {
+			class.BaseClass = baseClass.Name()
+		}
+
+		constructors := classes[i].Constructors()
+		for ci := range constructors {
+			class.Constructors = append(class.Constructors, ConstructorDescription{
+				ArgumentTypes: describeTypes(constructors[ci].ArgumentTypes()),
+			})
+		}
+		sort.Slice(class.Constructors, func(i, j int) bool {
+			return len(class.Constructors[i].ArgumentTypes) < len(class.Constructors[j].ArgumentTypes)
+		})
+
+		methods := classes[i].Methods()
+		for mi := range methods {
+			class.Methods = append(class.Methods, FunctionDescription{
+				Name:          methods[mi].Symbol(),
+				ReturnType:    describeOptionalType(methods[mi].ReturnType()),
+				ArgumentTypes: describeTypes(methods[mi].ArgumentTypes()),
+			})
+		}
+		sortFunctions(class.Methods)
+
+		staticMethods := classes[i].StaticMethods()
+		for mi := range staticMethods {
+			class.StaticMethods = append(class.StaticMethods, FunctionDescription{
+				Name:          staticMethods[mi].Symbol(),
+				ReturnType:    describeOptionalType(staticMethods[mi].ReturnType()),
+				ArgumentTypes: describeTypes(staticMethods[mi].ArgumentTypes()),
+			})
+		}
+		sortFunctions(class.StaticMethods)
+
+		properties := classes[i].Properties()
+		for pi := range properties {
+			class.Properties = append(class.Properties, PropertyDescription{
+				Name:       properties[pi].Name(),
+				GetterType: describeOptionalType(properties[pi].GetterType()),
+				SetterType: describeOptionalType(properties[pi].SetterType()),
+				ReadOnly:   properties[pi].ReadOnly(),
+			})
+		}
+		sortProperties(class.Properties)
+
+		staticProperties := classes[i].StaticProperties()
+		for pi := range staticProperties {
+			class.StaticProperties = append(class.StaticProperties, PropertyDescription{
+				Name:       staticProperties[pi].Name(),
+				GetterType: describeOptionalType(staticProperties[pi].GetterType()),
+				SetterType: describeOptionalType(staticProperties[pi].SetterType()),
+				ReadOnly:   staticProperties[pi].ReadOnly(),
+			})
+		}
+		sortProperties(class.StaticProperties)
+
+		description.Classes = append(description.Classes, class)
+	}
+	sort.Slice(description.Classes, func(i, j int) bool {
+		return description.Classes[i].Name < description.Classes[j].Name
+	})
+
+	enums := engine.GetEnums()
+	for i := range enums {
+		enum := EnumDescription{
+			Name:   enums[i].Name(),
+			Type:   describeType(enums[i].Type()),
+			Values: []EnumValueDescription{},
+		}
+
+		values := enums[i].Values()
+		for vi := range values {
+			enum.Values = append(enum.Values, EnumValueDescription{
+				Name:  values[vi].Name(),
+				Value: values[vi].Value(),
+			})
+		}
+		sort.Slice(enum.Values, func(i, j int) bool {
+			return enum.Values[i].Name < enum.Values[j].Name
+		})
+
+		description.Enums = append(description.Enums, enum)
+	}
+	sort.Slice(description.Enums, func(i, j int) bool {
+		return description.Enums[i].Name < description.Enums[j].Name
+	})
+
+	constants := engine.GetConstants()
+	for i := range constants {
+		description.Constants = append(description.Constants, ConstantDescription{
+			Name:  constants[i].Name(),
+			Type:  describeType(constants[i].Type()),
+			Value: constants[i].Value(),
+		})
+	}
+	sort.Slice(description.Constants, func(i, j int) bool {
+		return description.Constants[i].Name < description.Constants[j].Name
+	})
+
+	// The engine already returns the user types sorted by name.
+	userTypes := engine.GetUserTypes()
+	for i := range userTypes {
+		description.UserTypes = append(description.UserTypes, UserTypeDescription{
+			Name:         userTypes[i].Name(),
+			GoType:       userTypes[i].Type(),
+			DeclaredType: userTypes[i].DeclaredType(),
+		})
+	}
+
+	return description
+}
+
+// DescribeWasm instantiates the given WASM module, calls the init function
+// to make Emscripten register the bindings and returns their description.
+// When initFunction is empty, DefaultInitFunction is used.
+func DescribeWasm(wasm []byte, initFunction string) (*Description, error) {
+	var description *Description
+	err := runModule(wasm, initFunction, func(engine embind.Engine) error {
+		description = Describe(engine)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return description, nil
+}
+
+// WriteJSON writes the description as indented JSON.
+func (d *Description) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+// WriteDescription writes the description of the bindings that are
+// registered in the given engine to bindings.json in the output directory
+// of the options, and to bindings.d.ts when TypeScript is enabled in the
+// options. The file prefix of the options is prepended to the file names.
+func WriteDescription(engine embind.Engine, options Options) error {
+	description := Describe(engine)
+
+	type descriptionFile struct {
+		name  string
+		write func(w io.Writer) error
+	}
+
+	files := []descriptionFile{
+		{name: "bindings.json", write: description.WriteJSON},
+	}
+
+	if options.TypeScript {
+		files = append(files, descriptionFile{name: "bindings.d.ts", write: description.WriteTypeScript})
+	}
+
+	for _, file := range files {
+		filePath := filepath.Join(options.outDir(), options.FilePrefix+file.name)
+		fileWriter, err := os.Create(filePath)
+		if err != nil {
+			return err
+		}
+
+		err = file.write(fileWriter)
+		closeErr := fileWriter.Close()
+		if err != nil {
+			return fmt.Errorf("could not write %s: %w", filePath, err)
+		}
+		if closeErr != nil {
+			return closeErr
+		}
+	}
+
+	return nil
+}
+
+// GenerateDescription instantiates the given WASM module, calls the init
+// function of the options to make Emscripten register the bindings and
+// writes their description like WriteDescription.
+func GenerateDescription(wasm []byte, options Options) error {
+	return runModule(wasm, options.InitFunction, func(engine embind.Engine) error {
+		return WriteDescription(engine, options)
+	})
+}
@@ -0,0 +1,315 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ChangeKind is the kind of a change between two descriptions.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change is a single difference between two descriptions. Old and New
+// contain the signature or value before and after the change, and are empty
+// for additions and removals respectively.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Breaking bool       `json:"breaking"`
+	Element  string     `json:"element"`
+	Name     string     `json:"name"`
+	Old      string     `json:"old,omitempty"`
+	New      string     `json:"new,omitempty"`
+}
+
+// DescriptionDiff contains all the differences between two descriptions.
+type DescriptionDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// HasBreakingChanges returns whether any of the changes breaks code that
+// uses the old bindings.
+func (d *DescriptionDiff) HasBreakingChanges() bool {
+	for i := range d.Changes {
+		if d.Changes[i].Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the changes in a human-readable format, one per line.
+func (d *DescriptionDiff) WriteText(w io.Writer) error {
+	for _, change := range d.Changes {
+		var line string
+		switch change.Kind {
+		case ChangeAdded:
+			line = fmt.Sprintf("+ %s %s: %s", change.Element, change.Name, change.New)
+		case ChangeRemoved:
+			line = fmt.Sprintf("- %s %s: %s", change.Element, change.Name, change.Old)
+		default:
+			line = fmt.Sprintf("~ %s %s: %s -> %s", change.Element, change.Name, change.Old, change.New)
+		}
+		if change.Breaking {
+			line += " (breaking)"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the changes as indented JSON.
+func (d *DescriptionDiff) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(d)
+}
+
+func typeSignature(t *TypeDescription) string {
+	if t == nil {
+		return "void"
+	}
+	return t.Name
+}
+
+func argumentsSignature(types []TypeDescription) string {
+	arguments := make([]string, len(types))
+	for i := range types {
+		arguments[i] = typeSignature(&types[i])
+	}
+	return strings.Join(arguments, ", ")
+}
+
+func functionSignature(function FunctionDescription) string {
+	return fmt.Sprintf("%s(%s) %s", function.Name, argumentsSignature(function.ArgumentTypes), typeSignature(function.ReturnType))
+}
+
+func propertySignature(property PropertyDescription) string {
+	getter := typeSignature(property.GetterType)
+	if property.ReadOnly || property.SetterType == nil {
+		return "readonly " + getter
+	}
+	if setter := typeSignature(property.SetterType); setter != getter {
+		return fmt.Sprintf("%s (set %s)", getter, setter)
+	}
+	return getter
+}
+
+// differ collects the changes between two descriptions.
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(kind ChangeKind, breaking bool, element, name, oldValue, newValue string) {
+	d.changes = append(d.changes, Change{
+		Kind:     kind,
+		Breaking: breaking,
+		Element:  element,
+		Name:     name,
+		Old:      oldValue,
+		New:      newValue,
+	})
+}
+
+// compare compares two sets of signatures by key. Additions are never
+// breaking, removals and changed signatures always are.
+func (d *differ) compare(element string, oldSignatures, newSignatures map[string]string) {
+	for _, key := range sortedKeys(oldSignatures) {
+		newSignature, ok := newSignatures[key]
+		if !ok {
+			d.add(ChangeRemoved, true, element, key, oldSignatures[key], "")
+		} else if newSignature != oldSignatures[key] {
+			d.add(ChangeChanged, true, element, key, oldSignatures[key], newSignature)
+		}
+	}
+
+	for _, key := range sortedKeys(newSignatures) {
+		if _, ok := oldSignatures[key]; !ok {
+			d.add(ChangeAdded, false, element, key, "", newSignatures[key])
+		}
+	}
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// functionSignatures keys the functions by their name and argument count,
+// as Embind only allows overloads with a different number of arguments.
+func functionSignatures(prefix string, functions []FunctionDescription) map[string]string {
+	signatures := map[string]string{}
+	for _, function := range functions {
+		signatures[fmt.Sprintf("%s%s/%d", prefix, function.Name, len(function.ArgumentTypes))] = functionSignature(function)
+	}
+	return signatures
+}
+
+// compareProperties compares the properties by name. Changing the getter
+// type breaks reading the property and making it read-only or changing the
+// setter type breaks writing it, making a read-only property writable
+// breaks nothing.
+func (d *differ) compareProperties(element, prefix string, oldProperties, newProperties []PropertyDescription) {
+	oldByName := map[string]PropertyDescription{}
+	for _, property := range oldProperties {
+		oldByName[property.Name] = property
+	}
+	newByName := map[string]PropertyDescription{}
+	for _, property := range newProperties {
+		newByName[property.Name] = property
+	}
+
+	isReadOnly := func(property PropertyDescription) bool {
+		return property.ReadOnly || property.SetterType == nil
+	}
+
+	for _, name := range sortedKeys(oldByName) {
+		oldProperty := oldByName[name]
+		newProperty, ok := newByName[name]
+		if !ok {
+			d.add(ChangeRemoved, true, element, prefix+name, propertySignature(oldProperty), "")
+			continue
+		}
+
+		oldSignature, newSignature := propertySignature(oldProperty), propertySignature(newProperty)
+		if oldSignature == newSignature {
+			continue
+		}
+
+		breaking := typeSignature(oldProperty.GetterType) != typeSignature(newProperty.GetterType)
+		if !isReadOnly(oldProperty) {
+			breaking = breaking || isReadOnly(newProperty) || typeSignature(oldProperty.SetterType) != typeSignature(newProperty.SetterType)
+		}
+		d.add(ChangeChanged, breaking, element, prefix+name, oldSignature, newSignature)
+	}
+
+	for _, name := range sortedKeys(newByName) {
+		if _, ok := oldByName[name]; !ok {
+			d.add(ChangeAdded, false, element, prefix+name, "", propertySignature(newByName[name]))
+		}
+	}
+}
+
+// Diff compares the bindings of two descriptions. Removed and changed
+// functions, classes, methods, properties, enum values and constants are
+// breaking changes, additions are not. The only change that isn't breaking
+// is a read-only property that becomes writable.
+func Diff(from, to *Description) *DescriptionDiff {
+	d := &differ{changes: []Change{}}
+
+	d.compare("function", functionSignatures("", from.Functions), functionSignatures("", to.Functions))
+
+	fromClasses := map[string]ClassDescription{}
+	for _, class := range from.Classes {
+		fromClasses[class.Name] = class
+	}
+	toClasses := map[string]ClassDescription{}
+	for _, class := range to.Classes {
+		toClasses[class.Name] = class
+	}
+
+	classSignature := func(class ClassDescription) string {
+		if class.BaseClass != "" {
+			return class.Name + " : " + class.BaseClass
+		}
+		return class.Name
+	}
+
+	for _, name := range sortedKeys(fromClasses) {
+		fromClass := fromClasses[name]
+		toClass, ok := toClasses[name]
+		if !ok {
+			d.add(ChangeRemoved, true, "class", name, classSignature(fromClass), "")
+			continue
+		}
+
+		if fromClass.BaseClass != toClass.BaseClass {
+			d.add(ChangeChanged, true, "class", name, classSignature(fromClass), classSignature(toClass))
+		}
+
+		fromConstructors := map[string]string{}
+		for _, constructor := range fromClass.Constructors {
+			fromConstructors[fmt.Sprintf("%s/%d", name, len(constructor.ArgumentTypes))] = fmt.Sprintf("%s(%s)", name, argumentsSignature(constructor.ArgumentTypes))
+		}
+		toConstructors := map[string]string{}
+		for _, constructor := range toClass.Constructors {
+			toConstructors[fmt.Sprintf("%s/%d", name, len(constructor.ArgumentTypes))] = fmt.Sprintf("%s(%s)", name, argumentsSignature(constructor.ArgumentTypes))
+		}
+		d.compare("constructor", fromConstructors, toConstructors)
+
+		d.compare("method", functionSignatures(name+".", fromClass.Methods), functionSignatures(name+".", toClass.Methods))
+		d.compare("static method", functionSignatures(name+".", fromClass.StaticMethods), functionSignatures(name+".", toClass.StaticMethods))
+		d.compareProperties("property", name+".", fromClass.Properties, toClass.Properties)
+		d.compareProperties("static property", name+".", fromClass.StaticProperties, toClass.StaticProperties)
+	}
+
+	for _, name := range sortedKeys(toClasses) {
+		if _, ok := fromClasses[name]; !ok {
+			d.add(ChangeAdded, false, "class", name, "", classSignature(toClasses[name]))
+		}
+	}
+
+	fromEnums := map[string]EnumDescription{}
+	for _, enum := range from.Enums {
+		fromEnums[enum.Name] = enum
+	}
+	toEnums := map[string]EnumDescription{}
+	for _, enum := range to.Enums {
+		toEnums[enum.Name] = enum
+	}
+
+	enumValues := func(enum EnumDescription) map[string]string {
+		values := map[string]string{}
+		for _, value := range enum.Values {
+			values[enum.Name+"."+value.Name] = fmt.Sprintf("%v", value.Value)
+		}
+		return values
+	}
+
+	for _, name := range sortedKeys(fromEnums) {
+		fromEnum := fromEnums[name]
+		toEnum, ok := toEnums[name]
+		if !ok {
+			d.add(ChangeRemoved, true, "enum", name, fromEnum.Type.GoType, "")
+			continue
+		}
+
+		if fromEnum.Type.GoType != toEnum.Type.GoType {
+			d.add(ChangeChanged, true, "enum", name, fromEnum.Type.GoType, toEnum.Type.GoType)
+		}
+
+		d.compare("enum value", enumValues(fromEnum), enumValues(toEnum))
+	}
+
+	for _, name := range sortedKeys(toEnums) {
+		if _, ok := fromEnums[name]; !ok {
+			d.add(ChangeAdded, false, "enum", name, "", toEnums[name].Type.GoType)
+		}
+	}
+
+	constantSignatures := func(constants []ConstantDescription) map[string]string {
+		signatures := map[string]string{}
+		for _, constant := range constants {
+			signatures[constant.Name] = fmt.Sprintf("%s = %v", constant.Type.Name, constant.Value)
+		}
+		return signatures
+	}
+	d.compare("constant", constantSignatures(from.Constants), constantSignatures(to.Constants))
+
+	return &DescriptionDiff{
+		Changes: d.changes,
+	}
+}
@@ -1,21 +1,22 @@
 package generator
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"embed"
+	"errors"
 	"fmt"
 	"go/format"
-	"go/token"
+	"io"
+	"io/fs"
 	"log"
 	"os"
-	"path"
+	"path/filepath"
 	"reflect"
 	"sort"
-	"strconv"
 	"strings"
 	"text/template"
-	"unicode"
 
 	"github.com/jerbob92/wazero-emscripten-embind"
 
@@ -23,7 +24,6 @@ import (
 	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/imports/emscripten"
 	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
-	"golang.org/x/tools/go/packages"
 )
 
 var (
@@ -31,16 +31,24 @@ var (
 	templates embed.FS
 )
 
-func Generate(dir string, fileName string, wasm []byte, initFunction string) error {
-	fset := token.NewFileSet()
-	pkgs, err := packages.Load(&packages.Config{
-		Fset: fset,
-		Mode: packages.NeedSyntax | packages.NeedName | packages.NeedModule | packages.NeedTypes | packages.NeedTypesInfo,
-	}, fmt.Sprintf("file=%s", fileName))
+// Generate instantiates the given WASM module, calls the init function of
+// the options to make Emscripten register the bindings, and generates the
+// code for the bindings into the output directory of the options.
+func Generate(wasm []byte, options Options) error {
+	err := options.validate()
 	if err != nil {
 		return err
 	}
 
+	return runModule(wasm, options.InitFunction, func(engine embind.Engine) error {
+		return GenerateFromEngine(engine, options)
+	})
+}
+
+// runModule instantiates the given WASM module with an embind engine and
+// dummy implementations for the other imports, calls the init function and
+// calls fn with the engine while the module is still alive.
+func runModule(wasm []byte, initFunction string, fn func(engine embind.Engine) error) error {
 	ctx := context.Background()
 	runtimeConfig := wazero.NewRuntimeConfigInterpreter()
 	r := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
@@ -165,18 +173,37 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		return err
 	}
 
+	if initFunction == "" {
+		initFunction = DefaultInitFunction
+	}
+
 	initFunc := mod.ExportedFunction(initFunction)
 	if initFunc == nil {
-		log.Fatalf("init function %s does not exist", initFunction)
+		return fmt.Errorf("init function %s does not exist", initFunction)
 	}
 
-	res, err := initFunc.Call(ctx)
-	if res != nil {
-		return fmt.Errorf("could not call init function %w", err)
+	_, err = initFunc.Call(ctx)
+	if err != nil {
+		return fmt.Errorf("could not call init function: %w", err)
+	}
+
+	return fn(engine)
+}
+
+// GenerateFromEngine generates the code for the bindings that are registered
+// in the given engine into the output directory of the options. This can be
+// used to generate code for a module that needs custom initialization, the
+// module has to be instantiated with the engine attached to the context.
+func GenerateFromEngine(engine embind.Engine, options Options) error {
+	err := options.validate()
+	if err != nil {
+		return err
 	}
 
-	packageName := pkgs[0].Name
-	packagePath := pkgs[0].PkgPath
+	packageName, err := options.resolvePackageName()
+	if err != nil {
+		return err
+	}
 
 	templates, err := template.New("").
 		Funcs(TemplateFunctions). // Custom functions
@@ -186,40 +213,107 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 	}
 
 	data := TemplateData{
-		Pkg:       packageName,
-		PkgPath:   packagePath,
-		Symbols:   []TemplateSymbol{},
-		Constants: []TemplateConstant{},
-		Enums:     []TemplateEnum{},
-		Classes:   []TemplateClass{},
+		Pkg:           packageName,
+		AttachName:    options.FunctionPrefix + "Attach",
+		FunctionsName: options.FunctionPrefix + "Functions",
+		Symbols:       []TemplateSymbol{},
+		Constants:     []TemplateConstant{},
+		Enums:         []TemplateEnum{},
+		Classes:       []TemplateClass{},
+		UserTypes:     []TemplateUserType{},
+	}
+
+	// Keep track of the classes and enums that are filtered out, types that
+	// reference them fall back to generic types.
+	excludedClasses := map[string]bool{}
+	classes := engine.GetClasses()
+	for i := range classes {
+		if !options.isIncluded(classes[i].Name()) {
+			excludedClasses[classes[i].Name()] = true
+		}
 	}
 
-	generateGoName := func(name string) string {
-		if len(name) == 0 {
-			return name
+	excludedEnums := map[string]string{}
+	enums := engine.GetEnums()
+	for i := range enums {
+		if !options.isIncluded(enums[i].Name()) {
+			excludedEnums[enums[i].Name()] = enums[i].Type().Type()
 		}
-		upperFirst := string(unicode.ToUpper(rune(name[0]))) + name[1:]
-		return upperFirst
 	}
 
+	// User types are emscripten::val values with a declared type, they get
+	// a named Go type unless they are filtered out.
+	userTypes := map[string]bool{}
+	registeredUserTypes := engine.GetUserTypes()
+	for i := range registeredUserTypes {
+		userTypes[registeredUserTypes[i].Name()] = true
+	}
+
+	names := newNamer(options)
+
 	typeNameToGeneratedName := func(name string, isClass, isEnum, isArgument bool) string {
 		if isClass {
 			name = strings.TrimPrefix(name, "*")
-			name = "Class" + generateGoName(name)
-			name = "*" + name
-			if isArgument {
-				name = "embind.ClassBase"
+			if isArgument || excludedClasses[name] {
+				return "embind.ClassBase"
 			}
+			name = options.ClassPrefix + names.name(name, name)
+			name = "*" + name
 		} else if isEnum {
-			name = "Enum" + generateGoName(name)
+			// Optional enums are pointers to the enum.
+			pointer := ""
+			if strings.HasPrefix(name, "*") {
+				pointer = "*"
+				name = strings.TrimPrefix(name, "*")
+			}
+			if underlyingType, ok := excludedEnums[name]; ok {
+				return pointer + underlyingType
+			}
+			name = pointer + options.EnumPrefix + names.name(name, name)
+		} else if userTypes[name] {
+			if !options.isIncluded(name) {
+				return "any"
+			}
+			name = options.UserTypePrefix + names.name(name, name)
 		}
 
 		return name
 	}
 
+	// Keep track of the base classes of every class, starting with the direct
+	// base class, and the classes that other classes derive from. Arguments
+	// of these classes accept the derived classes as well.
+	baseClasses := map[string][]string{}
+	classesWithDerivedClasses := map[string]bool{}
+	for i := range classes {
+		for baseClass := classes[i].BaseClass(); baseClass != nil; baseClass = baseClass.BaseClass() {
+			baseClasses[classes[i].Name()] = append(baseClasses[classes[i].Name()], baseClass.Name())
+			classesWithDerivedClasses[baseClass.Name()] = true
+		}
+	}
+
+	classArgumentName := func(name string) string {
+		goName := options.ClassPrefix + names.name(name, name)
+		if classesWithDerivedClasses[name] {
+			return goName + "OrDerived"
+		}
+		return "*" + goName
+	}
+
+	argumentTypeToGeneratedName := func(name string, isClass, isEnum bool) string {
+		if isClass && options.TypedClassArguments {
+			className := strings.TrimPrefix(name, "*")
+			if !excludedClasses[className] {
+				return classArgumentName(className)
+			}
+		}
+
+		return typeNameToGeneratedName(name, isClass, isEnum, true)
+	}
+
 	typeNameToErrorValue := func(name string, isClass, isEnum bool) string {
 		convertedName := typeNameToGeneratedName(name, isClass, isEnum, false)
-		if isClass || convertedName == "any" || strings.HasPrefix(convertedName, "[]") || strings.HasPrefix(convertedName, "map[") {
+		if isClass || userTypes[name] || convertedName == "any" || strings.HasPrefix(convertedName, "*") || strings.HasPrefix(convertedName, "[]") || strings.HasPrefix(convertedName, "map[") {
 			return "nil"
 		}
 
@@ -237,12 +331,16 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 
 	constants := engine.GetConstants()
 	for i := range constants {
+		if !options.isIncluded(constants[i].Name()) {
+			continue
+		}
+
 		constantValue := constants[i].Value()
 		formattedConstantValue := fmt.Sprintf("%#v", constantValue)
 
 		constant := TemplateConstant{
 			Name:          constants[i].Name(),
-			GoName:        "Constant_" + constants[i].Name(),
+			GoName:        options.ConstantPrefix + names.verbatimName(constants[i].Name(), constants[i].Name()),
 			Value:         formattedConstantValue,
 			CanBeConstant: true,
 			GoType:        typeNameToGeneratedName(constants[i].Type().Type(), constants[i].Type().IsClass(), constants[i].Type().IsEnum(), false),
@@ -273,16 +371,20 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 
 	symbols := engine.GetSymbols()
 	for i := range symbols {
+		if !options.isIncluded(symbols[i].Symbol()) {
+			continue
+		}
+
 		exposedArgumentTypes := symbols[i].ArgumentTypes()
 		argumentTypes := make([]string, len(exposedArgumentTypes))
+		argumentTypeNames := make([]string, len(exposedArgumentTypes))
 		for i := range exposedArgumentTypes {
-			argumentTypes[i] = typeNameToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum(), true)
+			argumentTypes[i] = argumentTypeToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum())
+			argumentTypeNames[i] = exposedArgumentTypes[i].Name()
 		}
 
-		goName := generateGoName(symbols[i].Symbol())
-		if symbols[i].IsOverload() && symbols[i].OverloadCount() > 1 {
-			goName += strconv.Itoa(len(argumentTypes))
-		}
+		isOverload := symbols[i].IsOverload() && symbols[i].OverloadCount() > 1
+		goName := options.FunctionPrefix + names.overloadName(symbols[i].Symbol(), symbols[i].Symbol(), isOverload, argumentTypeNames)
 
 		symbol := TemplateSymbol{
 			Symbol:        symbols[i].Symbol(),
@@ -308,6 +410,15 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		return data.Symbols[i].GoName < data.Symbols[j].GoName
 	})
 
+	symbolNames := map[string][]string{}
+	for i := range data.Symbols {
+		symbolNames[data.Symbols[i].GoName] = append(symbolNames[data.Symbols[i].GoName], data.Symbols[i].Symbol)
+	}
+	err = names.checkCollisions("functions", symbolNames)
+	if err != nil {
+		return err
+	}
+
 	// Prevent duplicate names.
 	seenNames := map[string]bool{}
 	for i := range data.Symbols {
@@ -319,28 +430,54 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		seenNames[data.Symbols[i].GoName] = true
 	}
 
-	enums := engine.GetEnums()
 	for i := range enums {
+		if !options.isIncluded(enums[i].Name()) {
+			continue
+		}
+
 		enum := TemplateEnum{
-			Name:   enums[i].Name(),
-			GoName: "Enum" + generateGoName(enums[i].Name()),
-			GoType: typeNameToGeneratedName(enums[i].Type().Type(), enums[i].Type().IsClass(), enums[i].Type().IsEnum(), false),
-			Values: []TemplateEnumValue{},
+			Name:           enums[i].Name(),
+			GoName:         options.EnumPrefix + names.name(enums[i].Name(), enums[i].Name()),
+			GoType:         typeNameToGeneratedName(enums[i].Type().Type(), enums[i].Type().IsClass(), enums[i].Type().IsEnum(), false),
+			ValueSeparator: "_",
+			Values:         []TemplateEnumValue{},
 		}
 
+		if options.IdiomaticNames {
+			enum.ValueSeparator = ""
+		}
+
+		valueNames := map[string][]string{}
 		values := enums[i].Values()
-		for i := range values {
-			enum.Values = append(enum.Values, TemplateEnumValue{
-				Name:   values[i].Name(),
-				GoName: values[i].Name(),
-				Value:  fmt.Sprintf("%v", values[i].Value()),
-			})
+		for vi := range values {
+			value := TemplateEnumValue{
+				Name:   values[vi].Name(),
+				GoName: names.verbatimName(enum.Name+"."+values[vi].Name(), values[vi].Name()),
+				Value:  fmt.Sprintf("%v", values[vi].Value()),
+			}
+			valueNames[value.GoName] = append(valueNames[value.GoName], enum.Name+"."+value.Name)
+			enum.Values = append(enum.Values, value)
+		}
+
+		err = names.checkCollisions("enum values", valueNames)
+		if err != nil {
+			return err
 		}
 
 		sort.Slice(enum.Values, func(i, j int) bool {
 			return enum.Values[i].GoName < enum.Values[j].GoName
 		})
 
+		// Values can share their value, String() and the switches only use
+		// the first name for every value.
+		seenValues := map[string]bool{}
+		for vi := range enum.Values {
+			if !seenValues[enum.Values[vi].Value] {
+				seenValues[enum.Values[vi].Value] = true
+				enum.UniqueValues = append(enum.UniqueValues, enum.Values[vi])
+			}
+		}
+
 		data.Enums = append(data.Enums, enum)
 	}
 
@@ -348,20 +485,64 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		return data.Enums[i].GoName < data.Enums[j].GoName
 	})
 
-	classes := engine.GetClasses()
+	for i := range registeredUserTypes {
+		if !options.isIncluded(registeredUserTypes[i].Name()) {
+			continue
+		}
+
+		data.UserTypes = append(data.UserTypes, TemplateUserType{
+			Name:         registeredUserTypes[i].Name(),
+			GoName:       options.UserTypePrefix + names.name(registeredUserTypes[i].Name(), registeredUserTypes[i].Name()),
+			DeclaredType: registeredUserTypes[i].DeclaredType(),
+		})
+	}
+
+	sort.Slice(data.UserTypes, func(i, j int) bool {
+		return data.UserTypes[i].GoName < data.UserTypes[j].GoName
+	})
+
+	typeNames := map[string][]string{}
+	for i := range data.Enums {
+		typeNames[data.Enums[i].GoName] = append(typeNames[data.Enums[i].GoName], data.Enums[i].Name)
+	}
+	for i := range data.UserTypes {
+		typeNames[data.UserTypes[i].GoName] = append(typeNames[data.UserTypes[i].GoName], data.UserTypes[i].Name)
+	}
+
+	implementWrappers := map[string]string{}
+	implementReturnTypes := map[string]string{}
 	for i := range classes {
+		if excludedClasses[classes[i].Name()] {
+			continue
+		}
+
 		class := TemplateClass{
 			Name:         classes[i].Name(),
-			GoName:       "Class" + generateGoName(classes[i].Name()),
+			GoName:       options.ClassPrefix + names.name(classes[i].Name(), classes[i].Name()),
 			Constructors: []TemplateClassConstructor{},
 		}
+		typeNames[class.GoName] = append(typeNames[class.GoName], class.Name)
+
+		if options.TypedClassArguments {
+			class.HasDerivedClasses = classesWithDerivedClasses[class.Name]
+			if class.HasDerivedClasses {
+				class.ArgumentInterfaces = append(class.ArgumentInterfaces, class.GoName)
+			}
+
+			for _, baseClass := range baseClasses[class.Name] {
+				if !excludedClasses[baseClass] {
+					class.ArgumentInterfaces = append(class.ArgumentInterfaces, options.ClassPrefix+names.name(baseClass, baseClass))
+				}
+			}
+		}
+		memberNames := map[string][]string{}
 
 		constructors := classes[i].Constructors()
 		for ci := range constructors {
 			exposedArgumentTypes := constructors[ci].ArgumentTypes()
 			argumentTypes := make([]string, len(exposedArgumentTypes))
 			for i := range exposedArgumentTypes {
-				argumentTypes[i] = typeNameToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum(), true)
+				argumentTypes[i] = argumentTypeToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum())
 			}
 
 			constructor := TemplateClassConstructor{
@@ -380,7 +561,7 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		for pi := range properties {
 			property := TemplateClassProperty{
 				Name:       properties[pi].Name(),
-				GoName:     generateGoName(properties[pi].Name()),
+				GoName:     names.name(class.Name+"."+properties[pi].Name(), properties[pi].Name()),
 				ReadOnly:   properties[pi].ReadOnly(),
 				GetterType: "any",
 				ErrorValue: "nil",
@@ -395,12 +576,13 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 			if !property.ReadOnly {
 				setterType := properties[pi].SetterType()
 				if setterType != nil {
-					property.SetterType = typeNameToGeneratedName(setterType.Type(), setterType.IsClass(), setterType.IsEnum(), true)
+					property.SetterType = argumentTypeToGeneratedName(setterType.Type(), setterType.IsClass(), setterType.IsEnum())
 				} else {
 					property.SetterType = "any"
 				}
 			}
 
+			memberNames["GetProperty"+property.GoName] = append(memberNames["GetProperty"+property.GoName], class.Name+"."+property.Name)
 			class.Properties = append(class.Properties, property)
 		}
 
@@ -412,7 +594,7 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		for pi := range staticProperties {
 			property := TemplateClassProperty{
 				Name:       staticProperties[pi].Name(),
-				GoName:     generateGoName(staticProperties[pi].Name()),
+				GoName:     names.name(class.Name+"."+staticProperties[pi].Name(), staticProperties[pi].Name()),
 				ReadOnly:   staticProperties[pi].ReadOnly(),
 				GetterType: "any",
 				ErrorValue: "nil",
@@ -427,12 +609,13 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 			if !property.ReadOnly {
 				setterType := staticProperties[pi].SetterType()
 				if setterType != nil {
-					property.SetterType = typeNameToGeneratedName(setterType.Type(), setterType.IsClass(), setterType.IsEnum(), true)
+					property.SetterType = argumentTypeToGeneratedName(setterType.Type(), setterType.IsClass(), setterType.IsEnum())
 				} else {
 					property.SetterType = "any"
 				}
 			}
 
+			memberNames["GetStaticProperty"+property.GoName] = append(memberNames["GetStaticProperty"+property.GoName], class.Name+"."+property.Name)
 			class.StaticProperties = append(class.StaticProperties, property)
 		}
 
@@ -444,14 +627,14 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		for mi := range methods {
 			exposedArgumentTypes := methods[mi].ArgumentTypes()
 			argumentTypes := make([]string, len(exposedArgumentTypes))
+			argumentTypeNames := make([]string, len(exposedArgumentTypes))
 			for i := range exposedArgumentTypes {
-				argumentTypes[i] = typeNameToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum(), true)
+				argumentTypes[i] = argumentTypeToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum())
+				argumentTypeNames[i] = exposedArgumentTypes[i].Name()
 			}
 
-			goName := generateGoName(methods[mi].Symbol())
-			if methods[mi].IsOverload() && methods[mi].OverloadCount() > 1 {
-				goName += strconv.Itoa(len(argumentTypes))
-			}
+			isOverload := methods[mi].IsOverload() && methods[mi].OverloadCount() > 1
+			goName := names.overloadName(class.Name+"."+methods[mi].Symbol(), methods[mi].Symbol(), isOverload, argumentTypeNames)
 
 			method := TemplateClassMethod{
 				Name:          methods[mi].Symbol(),
@@ -467,6 +650,7 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 				method.ErrorValue = typeNameToErrorValue(returnType.Type(), returnType.IsClass(), returnType.IsEnum())
 			}
 
+			memberNames[method.GoName] = append(memberNames[method.GoName], class.Name+"."+method.Name)
 			class.Methods = append(class.Methods, method)
 		}
 
@@ -478,14 +662,14 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 		for smi := range staticMethods {
 			exposedArgumentTypes := staticMethods[smi].ArgumentTypes()
 			argumentTypes := make([]string, len(exposedArgumentTypes))
+			argumentTypeNames := make([]string, len(exposedArgumentTypes))
 			for i := range exposedArgumentTypes {
-				argumentTypes[i] = typeNameToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum(), true)
+				argumentTypes[i] = argumentTypeToGeneratedName(exposedArgumentTypes[i].Type(), exposedArgumentTypes[i].IsClass(), exposedArgumentTypes[i].IsEnum())
+				argumentTypeNames[i] = exposedArgumentTypes[i].Name()
 			}
 
-			goName := generateGoName(staticMethods[smi].Symbol())
-			if staticMethods[smi].IsOverload() && staticMethods[smi].OverloadCount() > 1 {
-				goName += strconv.Itoa(len(argumentTypes))
-			}
+			isOverload := staticMethods[smi].IsOverload() && staticMethods[smi].OverloadCount() > 1
+			goName := names.overloadName(class.Name+"."+staticMethods[smi].Symbol(), staticMethods[smi].Symbol(), isOverload, argumentTypeNames)
 
 			method := TemplateClassMethod{
 				Name:          staticMethods[smi].Symbol(),
@@ -501,6 +685,14 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 				method.ErrorValue = typeNameToErrorValue(returnType.Type(), returnType.IsClass(), returnType.IsEnum())
 			}
 
+			// Classes with allow_subclass get a static implement method that
+			// returns the wrapper class.
+			if method.Name == "implement" && returnType != nil && returnType.IsClass() {
+				implementWrappers[class.Name] = strings.TrimPrefix(returnType.Type(), "*")
+				implementReturnTypes[class.Name] = typeNameToGeneratedName(returnType.Type(), true, false, false)
+			}
+
+			memberNames["Static"+method.GoName] = append(memberNames["Static"+method.GoName], class.Name+"."+method.Name)
 			class.StaticMethods = append(class.StaticMethods, method)
 		}
 
@@ -508,54 +700,175 @@ func Generate(dir string, fileName string, wasm []byte, initFunction string) err
 			return class.StaticMethods[i].GoName < class.StaticMethods[j].GoName
 		})
 
+		err = names.checkCollisions("members", memberNames)
+		if err != nil {
+			return err
+		}
+
 		data.Classes = append(data.Classes, class)
 	}
 
+	err = names.checkCollisions("classes, enums and user types", typeNames)
+	if err != nil {
+		return err
+	}
+
+	addInheritance(data.Classes, baseClasses, names)
+
+	err = checkCastCollisions(data.Classes)
+	if err != nil {
+		return err
+	}
+
+	for i := range data.Classes {
+		class := &data.Classes[i]
+		wrapper, ok := implementWrappers[class.Name]
+		if !ok || len(baseClasses[wrapper]) == 0 || baseClasses[wrapper][0] != class.Name {
+			continue
+		}
+
+		class.Implementation = &TemplateClassImplementation{
+			GoName:     class.GoName + "Implementation",
+			MapperName: strings.ToLower(class.GoName[:1]) + class.GoName[1:] + "Implementation",
+			ReturnType: implementReturnTypes[class.Name],
+			Methods:    class.Methods,
+		}
+		typeNames[class.Implementation.GoName] = append(typeNames[class.Implementation.GoName], class.Name+" implementation")
+	}
+
+	err = names.checkCollisions("classes and implementations", typeNames)
+	if err != nil {
+		return err
+	}
+
 	sort.Slice(data.Classes, func(i, j int) bool {
 		return data.Classes[i].GoName < data.Classes[j].GoName
 	})
 
-	if len(data.Classes) > 0 {
-		err = ExecuteTemplate(templates, "classes.tmpl", path.Join(dir, "classes.go"), data)
-		if err != nil {
-			return err
-		}
-	} else {
-		_ = os.Remove(path.Join(dir, "classes.go"))
+	type generatedFile struct {
+		template string
+		name     string
+		generate bool
+
+		// Whether a file with this name is only removed when it has been
+		// generated, because it's a common name for a hand-written file.
+		keepHandWritten bool
 	}
-	if len(data.Constants) > 0 {
-		err = ExecuteTemplate(templates, "constants.tmpl", path.Join(dir, "constants.go"), data)
-		if err != nil {
-			return err
+
+	files := []generatedFile{
+		{template: "classes.tmpl", name: "classes.go", generate: len(data.Classes) > 0},
+		{template: "constants.tmpl", name: "constants.go", generate: len(data.Constants) > 0},
+		{template: "functions.tmpl", name: "functions.go", generate: len(data.Symbols) > 0},
+		{template: "enums.tmpl", name: "enums.go", generate: len(data.Enums) > 0},
+		{template: "user_types.tmpl", name: "user_types.go", generate: len(data.UserTypes) > 0, keepHandWritten: true},
+		{template: "engine.tmpl", name: "engine.go", generate: true},
+	}
+
+	if options.Interfaces || options.Fakes {
+		for i := range data.Symbols {
+			if data.Symbols[i].GoName == data.FunctionsName || data.Symbols[i].GoName == "New"+data.FunctionsName {
+				return fmt.Errorf("the function %s conflicts with the generated %s interface, add a rename for it", data.Symbols[i].Symbol, data.FunctionsName)
+			}
 		}
-	} else {
-		_ = os.Remove(path.Join(dir, "constants.go"))
 	}
-	if len(data.Symbols) > 0 {
-		err = ExecuteTemplate(templates, "functions.tmpl", path.Join(dir, "functions.go"), data)
+
+	if options.Fakes {
+		data.FakesImportEmbind = usesEmbindTypes(data)
+	}
+
+	// The interfaces and fakes are always in the list so that files from an
+	// earlier run with the options enabled are removed.
+	hasCode := len(data.Classes) > 0 || len(data.Symbols) > 0
+	files = append(files,
+		generatedFile{template: "interfaces.tmpl", name: "interfaces.go", generate: (options.Interfaces || options.Fakes) && hasCode, keepHandWritten: true},
+		generatedFile{template: "fakes.tmpl", name: "fakes.go", generate: options.Fakes && hasCode, keepHandWritten: true},
+	)
+
+	for _, file := range files {
+		filePath := filepath.Join(options.outDir(), options.FilePrefix+file.name)
+		if !file.generate && file.keepHandWritten {
+			err = removeGeneratedFile(filePath)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !file.generate {
+			_ = os.Remove(filePath)
+			continue
+		}
+
+		err = ExecuteTemplate(templates, file.template, filePath, data)
 		if err != nil {
 			return err
 		}
-	} else {
-		_ = os.Remove(path.Join(dir, "functions.go"))
 	}
-	if len(data.Enums) > 0 {
-		err = ExecuteTemplate(templates, "enums.tmpl", path.Join(dir, "enums.go"), data)
-		if err != nil {
-			return err
+
+	return nil
+}
+
+// generatedFileHeader is the first line of every generated file.
+const generatedFileHeader = "// Code generated by wazero-emscripten-embind, DO NOT EDIT."
+
+// removeGeneratedFile removes a file from an earlier run, but only when its
+// first line is the header of a generated file, so that hand-written files
+// with the same name are kept.
+func removeGeneratedFile(filePath string) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
 		}
-	} else {
-		_ = os.Remove(path.Join(dir, "enums.go"))
+		return fmt.Errorf("could not open %s: %w", filePath, err)
+	}
+
+	firstLine, err := bufio.NewReader(file).ReadString('\n')
+	file.Close()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("could not read %s: %w", filePath, err)
+	}
+
+	if strings.TrimRight(firstLine, "\r\n") != generatedFileHeader {
+		return nil
 	}
 
-	err = ExecuteTemplate(templates, "engine.tmpl", path.Join(dir, "engine.go"), data)
+	err = os.Remove(filePath)
 	if err != nil {
-		return err
+		return fmt.Errorf("could not remove %s: %w", filePath, err)
 	}
 
 	return nil
 }
 
+// usesEmbindTypes returns whether any of the generated signatures uses a type
+// from the embind package.
+func usesEmbindTypes(data TemplateData) bool {
+	types := []string{}
+	for _, symbol := range data.Symbols {
+		types = append(types, symbol.ReturnType)
+		types = append(types, symbol.ArgumentTypes...)
+	}
+
+	for _, class := range data.Classes {
+		for _, property := range append(append([]TemplateClassProperty{}, class.Properties...), class.StaticProperties...) {
+			types = append(types, property.GetterType, property.SetterType)
+		}
+		for _, method := range append(append([]TemplateClassMethod{}, class.Methods...), class.StaticMethods...) {
+			types = append(types, method.ReturnType)
+			types = append(types, method.ArgumentTypes...)
+		}
+	}
+
+	for _, goType := range types {
+		if strings.Contains(goType, "embind.") {
+			return true
+		}
+	}
+
+	return false
+}
+
 var TemplateFunctions = template.FuncMap{
 	"lower": strings.ToLower,
 }
@@ -586,12 +899,15 @@ func ExecuteTemplate(tmpl *template.Template, name string, path string, data Tem
 }
 
 type TemplateData struct {
-	Pkg       string
-	PkgPath   string
-	Enums     []TemplateEnum
-	Symbols   []TemplateSymbol
-	Constants []TemplateConstant
-	Classes   []TemplateClass
+	Pkg               string
+	AttachName        string
+	FunctionsName     string
+	FakesImportEmbind bool
+	Enums             []TemplateEnum
+	Symbols           []TemplateSymbol
+	Constants         []TemplateConstant
+	Classes           []TemplateClass
+	UserTypes         []TemplateUserType
 }
 
 type TemplateConstant struct {
@@ -605,10 +921,18 @@ type TemplateConstant struct {
 }
 
 type TemplateEnum struct {
-	Name   string
-	GoName string
-	GoType string
-	Values []TemplateEnumValue
+	Name           string
+	GoName         string
+	GoType         string
+	ValueSeparator string
+	Values         []TemplateEnumValue
+	UniqueValues   []TemplateEnumValue
+}
+
+type TemplateUserType struct {
+	Name         string
+	GoName       string
+	DeclaredType string
 }
 
 type TemplateEnumValue struct {
@@ -618,13 +942,17 @@ type TemplateEnumValue struct {
 }
 
 type TemplateClass struct {
-	Name             string
-	GoName           string
-	Constructors     []TemplateClassConstructor
-	Properties       []TemplateClassProperty
-	StaticProperties []TemplateClassProperty
-	Methods          []TemplateClassMethod
-	StaticMethods    []TemplateClassMethod
+	Name               string
+	GoName             string
+	HasDerivedClasses  bool
+	ArgumentInterfaces []string
+	Casts              []TemplateClassCast
+	Implementation     *TemplateClassImplementation
+	Constructors       []TemplateClassConstructor
+	Properties         []TemplateClassProperty
+	StaticProperties   []TemplateClassProperty
+	Methods            []TemplateClassMethod
+	StaticMethods      []TemplateClassMethod
 }
 
 type TemplateClassProperty struct {
@@ -644,6 +972,20 @@ type TemplateClassMethod struct {
 	ErrorValue    string
 }
 
+type TemplateClassCast struct {
+	MethodName string
+	Name       string
+	GoName     string
+	Downcast   bool
+}
+
+type TemplateClassImplementation struct {
+	GoName     string
+	MapperName string
+	ReturnType string
+	Methods    []TemplateClassMethod
+}
+
 type TemplateClassConstructor struct {
 	Name          string
 	ArgumentTypes []string
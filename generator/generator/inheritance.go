@@ -0,0 +1,124 @@
+package generator
+
+import (
+	"fmt"
+	"sort"
+)
+
+// addInheritance adds the methods and properties of the base classes to the
+// derived classes, unless the derived class has a member with the same name,
+// and adds the casts between the classes and their base classes. The base
+// classes map contains the names of the base classes of every class, starting
+// with the direct base class.
+func addInheritance(templateClasses []TemplateClass, baseClasses map[string][]string, names *namer) {
+	templateClassIndex := map[string]int{}
+	for i := range templateClasses {
+		templateClassIndex[templateClasses[i].Name] = i
+	}
+
+	// Copy the own members so that inherited members are only added once.
+	ownMethods := map[string][]TemplateClassMethod{}
+	ownProperties := map[string][]TemplateClassProperty{}
+	for i := range templateClasses {
+		ownMethods[templateClasses[i].Name] = append([]TemplateClassMethod{}, templateClasses[i].Methods...)
+		ownProperties[templateClasses[i].Name] = append([]TemplateClassProperty{}, templateClasses[i].Properties...)
+	}
+
+	for i := range templateClasses {
+		class := &templateClasses[i]
+		methodNames := map[string]bool{}
+		for _, method := range class.Methods {
+			methodNames[method.GoName] = true
+		}
+
+		propertyNames := map[string]bool{}
+		for _, property := range class.Properties {
+			propertyNames[property.GoName] = true
+		}
+
+		for _, baseClass := range baseClasses[class.Name] {
+			baseClassIndex, ok := templateClassIndex[baseClass]
+			if !ok {
+				continue
+			}
+
+			for _, method := range ownMethods[baseClass] {
+				if !methodNames[method.GoName] {
+					methodNames[method.GoName] = true
+					class.Methods = append(class.Methods, method)
+				}
+			}
+
+			for _, property := range ownProperties[baseClass] {
+				if !propertyNames[property.GoName] {
+					propertyNames[property.GoName] = true
+					class.Properties = append(class.Properties, property)
+				}
+			}
+
+			base := &templateClasses[baseClassIndex]
+			class.Casts = append(class.Casts, TemplateClassCast{
+				MethodName: "As" + names.name(base.Name, base.Name),
+				Name:       base.Name,
+				GoName:     base.GoName,
+			})
+
+			base.Casts = append(base.Casts, TemplateClassCast{
+				MethodName: "DowncastTo" + names.name(class.Name, class.Name),
+				Name:       class.Name,
+				GoName:     class.GoName,
+				Downcast:   true,
+			})
+		}
+	}
+
+	for i := range templateClasses {
+		class := &templateClasses[i]
+		sort.Slice(class.Methods, func(i, j int) bool {
+			return class.Methods[i].GoName < class.Methods[j].GoName
+		})
+		sort.Slice(class.Properties, func(i, j int) bool {
+			return class.Properties[i].GoName < class.Properties[j].GoName
+		})
+		sort.Slice(class.Casts, func(i, j int) bool {
+			return class.Casts[i].MethodName < class.Casts[j].MethodName
+		})
+	}
+}
+
+// checkCastCollisions returns an error when the name of a generated cast
+// method is already used by another method of the class.
+func checkCastCollisions(templateClasses []TemplateClass) error {
+	for _, class := range templateClasses {
+		methodNames := map[string]string{}
+		for _, name := range []string{"Clone", "Delete", "DeleteLater", "IsDeleted", "IsAliasOf", "CallMethod", "SetProperty", "GetProperty"} {
+			methodNames[name] = "generated method " + name
+		}
+		for _, method := range class.Methods {
+			methodNames[method.GoName] = "method " + method.Name
+		}
+		for _, method := range class.StaticMethods {
+			methodNames["Static"+method.GoName] = "static method " + method.Name
+		}
+		for _, property := range class.Properties {
+			methodNames["GetProperty"+property.GoName] = "property " + property.Name
+			if !property.ReadOnly {
+				methodNames["SetProperty"+property.GoName] = "property " + property.Name
+			}
+		}
+		for _, property := range class.StaticProperties {
+			methodNames["GetStaticProperty"+property.GoName] = "static property " + property.Name
+			if !property.ReadOnly {
+				methodNames["SetStaticProperty"+property.GoName] = "static property " + property.Name
+			}
+		}
+
+		for _, cast := range class.Casts {
+			if member, ok := methodNames[cast.MethodName]; ok {
+				return fmt.Errorf("the cast method %s of class %s conflicts with the %s, add a rename for it", cast.MethodName, class.Name, member)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package generator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms is the list of initialisms that are written in all caps
+// in idiomatic Go names. This is the list that golint uses.
+var commonInitialisms = map[string]bool{
+	"ACL":   true,
+	"API":   true,
+	"ASCII": true,
+	"CPU":   true,
+	"CSS":   true,
+	"DNS":   true,
+	"EOF":   true,
+	"GUID":  true,
+	"HTML":  true,
+	"HTTP":  true,
+	"HTTPS": true,
+	"ID":    true,
+	"IP":    true,
+	"JSON":  true,
+	"LHS":   true,
+	"QPS":   true,
+	"RAM":   true,
+	"RHS":   true,
+	"RPC":   true,
+	"SLA":   true,
+	"SMTP":  true,
+	"SQL":   true,
+	"SSH":   true,
+	"TCP":   true,
+	"TLS":   true,
+	"TTL":   true,
+	"UDP":   true,
+	"UI":    true,
+	"UID":   true,
+	"UUID":  true,
+	"URI":   true,
+	"URL":   true,
+	"UTF8":  true,
+	"VM":    true,
+	"XML":   true,
+	"XMPP":  true,
+	"XSRF":  true,
+	"XSS":   true,
+}
+
+// typeNameSuffixes contains the overload suffixes of the types that don't
+// result in a readable name when converted.
+var typeNameSuffixes = map[string]string{
+	"std::string":                      "String",
+	"std::basic_string<unsigned char>": "Bytes",
+	"std::wstring":                     "WString",
+	"std::u16string":                   "U16String",
+	"std::u32string":                   "U32String",
+	"emscripten::val":                  "Val",
+	"unsigned char":                    "Uchar",
+	"unsigned short":                   "Ushort",
+	"unsigned int":                     "Uint",
+	"unsigned long":                    "Ulong",
+	"signed char":                      "Schar",
+}
+
+// LoadRenames reads a rename map from a JSON file. The file contains an
+// object that maps embind names to Go names, see Options.Renames for the
+// format of the keys.
+func LoadRenames(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read rename file: %w", err)
+	}
+
+	renames := map[string]string{}
+	err = json.Unmarshal(data, &renames)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse rename file %s: %w", path, err)
+	}
+
+	return renames, nil
+}
+
+// namer converts embind names to Go names.
+type namer struct {
+	idiomatic bool
+	renames   map[string]string
+}
+
+func newNamer(options Options) *namer {
+	return &namer{
+		idiomatic: options.IdiomaticNames,
+		renames:   options.Renames,
+	}
+}
+
+// name returns the Go name for the embind name. The key is used to look up
+// the name in the rename map.
+func (n *namer) name(key string, name string) string {
+	if renamed, ok := n.renames[key]; ok {
+		return renamed
+	}
+
+	return n.convert(name)
+}
+
+// overloadName returns the Go name for a function or method. Overloads are
+// renamed by argument count first, and then by name. Without a rename,
+// overloads get the argument count as suffix, or the names of the argument
+// types when idiomatic naming is enabled.
+func (n *namer) overloadName(key string, name string, isOverload bool, argumentTypes []string) string {
+	if isOverload {
+		if renamed, ok := n.renames[key+"/"+strconv.Itoa(len(argumentTypes))]; ok {
+			return renamed
+		}
+	}
+
+	goName := n.name(key, name)
+	if !isOverload {
+		return goName
+	}
+
+	if !n.idiomatic {
+		return goName + strconv.Itoa(len(argumentTypes))
+	}
+
+	for i := range argumentTypes {
+		goName += typeNameSuffix(argumentTypes[i])
+	}
+
+	return goName
+}
+
+// verbatimName returns the Go name of a constant or an enum value. These
+// were never converted, so the name is kept as-is when idiomatic naming is
+// disabled.
+func (n *namer) verbatimName(key string, name string) string {
+	if renamed, ok := n.renames[key]; ok {
+		return renamed
+	}
+
+	if !n.idiomatic {
+		return name
+	}
+
+	return toCamelCase(name)
+}
+
+func (n *namer) convert(name string) string {
+	if n.idiomatic {
+		return toCamelCase(name)
+	}
+
+	if len(name) == 0 {
+		return name
+	}
+
+	return string(unicode.ToUpper(rune(name[0]))) + name[1:]
+}
+
+// checkCollisions returns an error when multiple embind names result in the
+// same Go name. It only checks when idiomatic naming is enabled, since the
+// conversion then is no longer one-to-one.
+func (n *namer) checkCollisions(kind string, names map[string][]string) error {
+	if !n.idiomatic {
+		return nil
+	}
+
+	goNames := make([]string, 0, len(names))
+	for goName := range names {
+		goNames = append(goNames, goName)
+	}
+	sort.Strings(goNames)
+
+	for _, goName := range goNames {
+		if len(names[goName]) > 1 {
+			embindNames := append([]string{}, names[goName]...)
+			sort.Strings(embindNames)
+			return fmt.Errorf("the %s %s all result in the Go name %s, add a rename for them", kind, strings.Join(embindNames, ", "), goName)
+		}
+	}
+
+	return nil
+}
+
+// typeNameSuffix returns the name of an embind type as used in overload
+// names.
+func typeNameSuffix(name string) string {
+	name = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(name), "*"))
+	name = strings.TrimSuffix(name, " const")
+	if strings.HasPrefix(name, "std::optional<") && strings.HasSuffix(name, ">") {
+		return "Optional" + typeNameSuffix(strings.TrimSuffix(strings.TrimPrefix(name, "std::optional<"), ">"))
+	}
+	if suffix, ok := typeNameSuffixes[name]; ok {
+		return suffix
+	}
+
+	name = strings.TrimPrefix(name, "std::")
+	name = strings.TrimPrefix(name, "emscripten::")
+	return toCamelCase(name)
+}
+
+// toCamelCase converts snake_case, camelCase and C++ namespaced names to Go
+// CamelCase, with common initialisms in all caps.
+func toCamelCase(name string) string {
+	words := splitWords(name)
+	builder := strings.Builder{}
+	for _, word := range words {
+		upper := strings.ToUpper(word)
+		if commonInitialisms[upper] {
+			builder.WriteString(upper)
+			continue
+		}
+
+		runes := []rune(word)
+		builder.WriteRune(unicode.ToUpper(runes[0]))
+		builder.WriteString(strings.ToLower(string(runes[1:])))
+	}
+
+	// Go names can't start with a digit.
+	goName := builder.String()
+	if goName != "" && unicode.IsDigit(rune(goName[0])) {
+		goName = "N" + goName
+	}
+
+	return goName
+}
+
+// splitWords splits a name into words. Everything that is not a letter or a
+// digit separates words, as does a change from lower to upper case. A run of
+// upper case letters is kept together, except for the last letter when it
+// starts a new word, like in HTTPServer.
+func splitWords(name string) []string {
+	words := []string{}
+	current := []rune{}
+	runes := []rune(name)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = []rune{}
+		}
+	}
+
+	for i, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+
+		if len(current) > 0 && unicode.IsUpper(r) {
+			previous := current[len(current)-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || unicode.IsDigit(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				flush()
+			}
+		}
+
+		current = append(current, r)
+	}
+
+	flush()
+
+	return words
+}
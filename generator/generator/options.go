@@ -0,0 +1,168 @@
+package generator
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path"
+)
+
+// DefaultInitFunction is the function that is called by default to make
+// Emscripten register the bindings.
+const DefaultInitFunction = "_initialize"
+
+// Options configures the naming and the contents of the generated code.
+type Options struct {
+	// OutDir is the directory to write the generated files to. When empty,
+	// the files are written to the current directory.
+	OutDir string
+
+	// PackageName is the name of the generated package. When empty, the
+	// package name is read from GoFile.
+	PackageName string
+
+	// GoFile is the file that contains the go:generate directive, it is only
+	// used to resolve the package name when PackageName is empty.
+	GoFile string
+
+	// InitFunction is the function that is called by Generate to make
+	// Emscripten register the bindings. When empty, DefaultInitFunction is
+	// used.
+	InitFunction string
+
+	// FilePrefix is prepended to the names of the generated files.
+	FilePrefix string
+
+	// ClassPrefix is prepended to the Go names of classes.
+	ClassPrefix string
+
+	// EnumPrefix is prepended to the Go names of enums.
+	EnumPrefix string
+
+	// UserTypePrefix is prepended to the Go names of the user types that are
+	// declared with EMSCRIPTEN_DECLARE_VAL_TYPE and registered with
+	// register_type.
+	UserTypePrefix string
+
+	// ConstantPrefix is prepended to the Go names of constants.
+	ConstantPrefix string
+
+	// FunctionPrefix is prepended to the Go names of functions and to the
+	// generated Attach function.
+	FunctionPrefix string
+
+	// Include contains patterns of the names of the functions, classes, enums
+	// and constants to generate. When empty, everything is included. The
+	// patterns use the syntax of path.Match.
+	Include []string
+
+	// Exclude contains patterns of the names of the functions, classes, enums
+	// and constants to skip. Exclude takes precedence over Include. The
+	// patterns use the syntax of path.Match.
+	Exclude []string
+
+	// IdiomaticNames converts snake_case and C++ namespaces to Go CamelCase
+	// with common initialisms in all caps, and names overloads after their
+	// argument types instead of their argument count. Enum values are
+	// joined to their enum name without underscore.
+	IdiomaticNames bool
+
+	// Renames maps embind names to Go names for the cases where the naming
+	// heuristic gets it wrong. The prefixes are still added to the renamed
+	// names. The keys are:
+	//  - "name" for functions, classes, enums, user types and constants.
+	//  - "Class.name" for methods, static methods and properties.
+	//  - "Enum.NAME" for enum values.
+	//  - "name/2" and "Class.name/2" for the overload with 2 arguments,
+	//    this replaces the complete name, including the overload suffix.
+	Renames map[string]string
+
+	// TypedClassArguments makes generated functions, methods, constructors
+	// and property setters take the concrete class instead of
+	// embind.ClassBase. For classes that other classes derive from, an
+	// interface named <Class>OrDerived is generated that is implemented by
+	// the class and all its derived classes.
+	TypedClassArguments bool
+
+	// Interfaces generates an interface for every class, and an interface
+	// with an implementation for the functions, so that code that uses them
+	// can be tested with fakes. They are written to interfaces.go.
+	Interfaces bool
+
+	// Fakes generates fake implementations of the interfaces to fakes.go.
+	// Enabling Fakes enables Interfaces as well.
+	Fakes bool
+
+	// TypeScript makes WriteDescription write a TypeScript declaration file
+	// next to the JSON description.
+	TypeScript bool
+}
+
+// DefaultOptions returns the options that result in the same code as the
+// generator always generated.
+func DefaultOptions() Options {
+	return Options{
+		InitFunction:   DefaultInitFunction,
+		ClassPrefix:    "Class",
+		EnumPrefix:     "Enum",
+		UserTypePrefix: "Type",
+		ConstantPrefix: "Constant_",
+	}
+}
+
+func (o Options) validate() error {
+	for _, pattern := range append(append([]string{}, o.Include...), o.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid include/exclude pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+func (o Options) outDir() string {
+	if o.OutDir == "" {
+		return "."
+	}
+	return o.OutDir
+}
+
+// resolvePackageName returns the package name of the options, or reads it
+// from the package clause of GoFile.
+func (o Options) resolvePackageName() (string, error) {
+	if o.PackageName != "" {
+		return o.PackageName, nil
+	}
+
+	if o.GoFile == "" {
+		return "", fmt.Errorf("no package name given and no Go file to read it from")
+	}
+
+	file, err := parser.ParseFile(token.NewFileSet(), o.GoFile, nil, parser.PackageClauseOnly)
+	if err != nil {
+		return "", fmt.Errorf("could not read the package name from %s: %w", o.GoFile, err)
+	}
+
+	return file.Name.Name, nil
+}
+
+// isIncluded returns whether the given embind name passes the include and
+// exclude filters.
+func (o Options) isIncluded(name string) bool {
+	for _, pattern := range o.Exclude {
+		if matched, _ := path.Match(pattern, name); matched {
+			return false
+		}
+	}
+
+	if len(o.Include) == 0 {
+		return true
+	}
+
+	for _, pattern := range o.Include {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var typeScriptIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// typeScriptName quotes names that are not valid TypeScript identifiers.
+func typeScriptName(name string) string {
+	if typeScriptIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// typeScriptType maps the Go type of a binding to a TypeScript type.
+func typeScriptType(t *TypeDescription) string {
+	if t == nil {
+		return "void"
+	}
+
+	if strings.HasPrefix(t.Name, "std::optional<") {
+		valueType := *t
+		valueType.Name = strings.TrimSuffix(strings.TrimPrefix(t.Name, "std::optional<"), ">")
+		if !t.IsClass {
+			valueType.GoType = strings.TrimPrefix(t.GoType, "*")
+		}
+		valueTypeScriptType := typeScriptType(&valueType)
+		if strings.Contains(valueTypeScriptType, "=>") {
+			valueTypeScriptType = "(" + valueTypeScriptType + ")"
+		}
+		return valueTypeScriptType + " | undefined"
+	}
+
+	if t.DeclaredType != "" {
+		return t.DeclaredType
+	}
+
+	if t.IsClass || t.IsEnum {
+		return typeScriptName(t.Name)
+	}
+
+	switch t.GoType {
+	case "":
+		return "void"
+	case "bool":
+		return "boolean"
+	case "int8", "uint8", "int16", "uint16", "int32", "uint32", "float32", "float64":
+		return "number"
+	case "int64", "uint64":
+		return "bigint"
+	case "string":
+		return "string"
+	case "[]int8", "[]uint8", "[]int16", "[]uint16", "[]int32", "[]uint32", "[]int64", "[]uint64", "[]float32", "[]float64":
+		return "ArrayLike<number | bigint>"
+	case "[]any":
+		return "any[]"
+	case "map[string]any":
+		return "Record<string, any>"
+	}
+
+	return "any"
+}
+
+func typeScriptArguments(types []TypeDescription) string {
+	arguments := make([]string, len(types))
+	for i := range types {
+		arguments[i] = fmt.Sprintf("arg%d: %s", i, typeScriptType(&types[i]))
+	}
+	return strings.Join(arguments, ", ")
+}
+
+func typeScriptPropertyType(property PropertyDescription) string {
+	if property.GetterType != nil {
+		return typeScriptType(property.GetterType)
+	}
+	return typeScriptType(property.SetterType)
+}
+
+func typeScriptValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return fmt.Sprintf("%q", v)
+	case bool, int8, uint8, int16, uint16, int32, uint32, float32, float64:
+		return fmt.Sprintf("%v", v)
+	case int64, uint64:
+		return fmt.Sprintf("%vn", v)
+	}
+	return ""
+}
+
+// WriteTypeScript writes the description as a TypeScript declaration file,
+// in the same shape as the declarations that Emscripten emits for Embind.
+func (d *Description) WriteTypeScript(w io.Writer) error {
+	out := bufio.NewWriter(w)
+
+	fmt.Fprintln(out, "// Code generated by wazero-emscripten-embind, DO NOT EDIT.")
+
+	if len(d.UserTypes) > 0 {
+		fmt.Fprintln(out)
+	}
+	for _, userType := range d.UserTypes {
+		fmt.Fprintf(out, "export type %s = %s;\n", typeScriptName(userType.Name), userType.DeclaredType)
+	}
+
+	for _, enum := range d.Enums {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "export interface %sValue<T extends number> {\n", enum.Name)
+		fmt.Fprintln(out, "  value: T;")
+		fmt.Fprintln(out, "}")
+
+		values := make([]string, len(enum.Values))
+		for i, value := range enum.Values {
+			values[i] = fmt.Sprintf("%sValue<%v>", enum.Name, value.Value)
+		}
+		if len(values) == 0 {
+			values = append(values, "never")
+		}
+		fmt.Fprintf(out, "export type %s = %s;\n", typeScriptName(enum.Name), strings.Join(values, "|"))
+	}
+
+	for _, class := range d.Classes {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "export interface %s", typeScriptName(class.Name))
+		if class.BaseClass != "" {
+			fmt.Fprintf(out, " extends %s", typeScriptName(class.BaseClass))
+		}
+		fmt.Fprintln(out, " {")
+		for _, property := range class.Properties {
+			readOnly := ""
+			if property.ReadOnly {
+				readOnly = "readonly "
+			}
+			fmt.Fprintf(out, "  %s%s: %s;\n", readOnly, typeScriptName(property.Name), typeScriptPropertyType(property))
+		}
+		for _, method := range class.Methods {
+			fmt.Fprintf(out, "  %s(%s): %s;\n", typeScriptName(method.Name), typeScriptArguments(method.ArgumentTypes), typeScriptType(method.ReturnType))
+		}
+		fmt.Fprintln(out, "  delete(): void;")
+		fmt.Fprintln(out, "}")
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "interface EmbindModule {")
+	classNames := map[string]struct{}{}
+	for _, class := range d.Classes {
+		classNames[class.Name] = struct{}{}
+		fmt.Fprintf(out, "  %s: {\n", typeScriptName(class.Name))
+		for _, constructor := range class.Constructors {
+			fmt.Fprintf(out, "    new(%s): %s;\n", typeScriptArguments(constructor.ArgumentTypes), typeScriptName(class.Name))
+		}
+		for _, property := range class.StaticProperties {
+			readOnly := ""
+			if property.ReadOnly {
+				readOnly = "readonly "
+			}
+			fmt.Fprintf(out, "    %s%s: %s;\n", readOnly, typeScriptName(property.Name), typeScriptPropertyType(property))
+		}
+		for _, method := range class.StaticMethods {
+			fmt.Fprintf(out, "    %s(%s): %s;\n", typeScriptName(method.Name), typeScriptArguments(method.ArgumentTypes), typeScriptType(method.ReturnType))
+		}
+		fmt.Fprintln(out, "  };")
+	}
+	for _, enum := range d.Enums {
+		values := make([]string, len(enum.Values))
+		for i, value := range enum.Values {
+			values[i] = fmt.Sprintf("%s: %sValue<%v>", typeScriptName(value.Name), enum.Name, value.Value)
+		}
+		fmt.Fprintf(out, "  %s: {%s};\n", typeScriptName(enum.Name), strings.Join(values, ", "))
+	}
+	for _, function := range d.Functions {
+		// Classes are registered as symbols as well, their constructors
+		// are already declared above.
+		if _, isClass := classNames[function.Name]; isClass {
+			continue
+		}
+		fmt.Fprintf(out, "  %s(%s): %s;\n", typeScriptName(function.Name), typeScriptArguments(function.ArgumentTypes), typeScriptType(function.ReturnType))
+	}
+	for _, constant := range d.Constants {
+		constantType := typeScriptType(&constant.Type)
+		if value := typeScriptValue(constant.Value); value != "" && !constant.Type.IsClass && !constant.Type.IsEnum {
+			constantType = value
+		}
+		fmt.Fprintf(out, "  readonly %s: %s;\n", typeScriptName(constant.Name), constantType)
+	}
+	fmt.Fprintln(out, "}")
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "export type MainModule = EmbindModule;")
+
+	return out.Flush()
+}
@@ -6,41 +6,195 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jerbob92/wazero-emscripten-embind/generator/generator"
 )
 
 var (
-	packagePath  string
-	fileName     string
-	initFunction *string
-	wasm         *string
-	verbose      *bool
+	fileName       string
+	initFunction   *string
+	wasm           *string
+	verbose        *bool
+	outputDir      *string
+	packageName    *string
+	filePrefix     *string
+	classPrefix    *string
+	enumPrefix     *string
+	userTypePrefix *string
+	constantPrefix *string
+	functionPrefix *string
+	include        *string
+	exclude        *string
+	idiomaticNames *bool
+	renames        *string
+	typedClassArgs *bool
+	interfaces     *bool
+	fakes          *bool
+	description    *bool
+	typeScript     *bool
 )
 
 func init() {
+	defaultOptions := generator.DefaultOptions()
+
 	fileName = os.Getenv("GOFILE")
 	wasm = flag.String("wasm", "", "the wasm file to process")
 	initFunction = flag.String("init", "_initialize", "the function to execute to make Emscripten register the types")
 	verbose = flag.Bool("v", false, "enable verbose logging")
+	outputDir = flag.String("out", ".", "the directory to write the generated files to")
+	packageName = flag.String("package", "", "the package name of the generated code, defaults to the package of $GOFILE, or the name of the output directory when -out is given")
+	filePrefix = flag.String("file-prefix", "", "the prefix for the names of the generated files")
+	classPrefix = flag.String("class-prefix", defaultOptions.ClassPrefix, "the prefix for the Go names of classes")
+	enumPrefix = flag.String("enum-prefix", defaultOptions.EnumPrefix, "the prefix for the Go names of enums")
+	userTypePrefix = flag.String("user-type-prefix", defaultOptions.UserTypePrefix, "the prefix for the Go names of user types")
+	constantPrefix = flag.String("constant-prefix", defaultOptions.ConstantPrefix, "the prefix for the Go names of constants")
+	functionPrefix = flag.String("function-prefix", defaultOptions.FunctionPrefix, "the prefix for the Go names of functions and the Attach function")
+	include = flag.String("include", "", "comma separated list of patterns of the functions, classes, enums and constants to generate, all are included when empty")
+	exclude = flag.String("exclude", "", "comma separated list of patterns of the functions, classes, enums and constants to skip")
+	idiomaticNames = flag.Bool("idiomatic-names", false, "convert names to Go CamelCase with initialisms and name overloads after their argument types")
+	renames = flag.String("renames", "", "a JSON file that maps embind names to Go names")
+	typedClassArgs = flag.Bool("typed-class-arguments", false, "use the generated class types for class arguments instead of embind.ClassBase")
+	interfaces = flag.Bool("interfaces", false, "generate interfaces for the classes and functions")
+	fakes = flag.Bool("fakes", false, "generate fake implementations of the interfaces, implies -interfaces")
+	description = flag.Bool("description", false, "write a JSON description of the bindings instead of Go code")
+	typeScript = flag.Bool("typescript", false, "also write a TypeScript declaration file, implies -description")
 }
 
 func Usage() {
 	fmt.Fprintf(os.Stderr, "Usage of wazero-emscripten-embind/generator:\n")
-	fmt.Fprintf(os.Stderr, "TODO\n")
+	fmt.Fprintf(os.Stderr, "\tgenerator -wasm=path/to/file.wasm [flags]\n")
+	fmt.Fprintf(os.Stderr, "\tgenerator diff [flags] old.wasm new.wasm\n\n")
+	fmt.Fprintf(os.Stderr, "Generates typed Go code for the Embind bindings in the given wasm file.\n")
+	fmt.Fprintf(os.Stderr, "This is meant to be used with go:generate, for example:\n\n")
+	fmt.Fprintf(os.Stderr, "\t//go:generate go run github.com/jerbob92/wazero-emscripten-embind/generator -wasm=../wasm/embind.wasm\n\n")
+	fmt.Fprintf(os.Stderr, "The include and exclude patterns use the syntax of path.Match and are matched against the Embind names.\n")
+	fmt.Fprintf(os.Stderr, "The rename file is a JSON object with Embind names as keys, like \"my_function\", \"MyClass.my_method\",\n")
+	fmt.Fprintf(os.Stderr, "\"MyEnum.VALUE\" or \"my_overload/2\" for the overload with 2 arguments, and Go names as values.\n\n")
+	fmt.Fprintf(os.Stderr, "Flags:\n")
+	flag.PrintDefaults()
+}
+
+func splitPatterns(patterns string) []string {
+	if patterns == "" {
+		return nil
+	}
+
+	splitPatterns := strings.Split(patterns, ",")
+	for i := range splitPatterns {
+		splitPatterns[i] = strings.TrimSpace(splitPatterns[i])
+	}
+
+	return splitPatterns
+}
+
+// runDiff compares the bindings of two wasm files and exits with 1 when
+// there are breaking changes.
+func runDiff(args []string) {
+	diffFlags := flag.NewFlagSet("diff", flag.ExitOnError)
+	diffInitFunction := diffFlags.String("init", generator.DefaultInitFunction, "the function to execute to make Emscripten register the types")
+	diffJSON := diffFlags.Bool("json", false, "write the changes as JSON")
+	diffFlags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of wazero-emscripten-embind/generator diff:\n")
+		fmt.Fprintf(os.Stderr, "\tgenerator diff [flags] old.wasm new.wasm\n\n")
+		fmt.Fprintf(os.Stderr, "Reports the added, removed and changed bindings between two wasm files.\n")
+		fmt.Fprintf(os.Stderr, "Exits with status 1 when there are breaking changes.\n\n")
+		fmt.Fprintf(os.Stderr, "Flags:\n")
+		diffFlags.PrintDefaults()
+	}
+	diffFlags.Parse(args)
+
+	if diffFlags.NArg() != 2 {
+		diffFlags.Usage()
+		log.Fatal("Expected an old and a new wasm file")
+	}
+
+	descriptions := make([]*generator.Description, 2)
+	for i, wasmFile := range diffFlags.Args() {
+		wasmData, err := os.ReadFile(wasmFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		descriptions[i], err = generator.DescribeWasm(wasmData, *diffInitFunction)
+		if err != nil {
+			log.Fatalf("could not describe %s: %s", wasmFile, err)
+		}
+	}
+
+	diff := generator.Diff(descriptions[0], descriptions[1])
+
+	var err error
+	if *diffJSON {
+		err = diff.WriteJSON(os.Stdout)
+	} else {
+		err = diff.WriteText(os.Stdout)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if diff.HasBreakingChanges() {
+		os.Exit(1)
+	}
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "diff" {
+		runDiff(os.Args[2:])
+		return
+	}
+
 	flag.Usage = Usage
 	flag.Parse()
 
-	dir, err := filepath.Abs(".")
+	if *wasm == "" {
+		flag.Usage()
+		log.Fatal("No wasm file given")
+	}
+
+	dir, err := filepath.Abs(*outputDir)
 	if err != nil {
-		panic(err)
+		log.Fatal(err)
 	}
 
-	if wasm == nil {
-		log.Fatal("No wasm file given")
+	options := generator.Options{
+		OutDir:         dir,
+		PackageName:    *packageName,
+		GoFile:         fileName,
+		InitFunction:   *initFunction,
+		FilePrefix:     *filePrefix,
+		ClassPrefix:    *classPrefix,
+		EnumPrefix:     *enumPrefix,
+		UserTypePrefix: *userTypePrefix,
+		ConstantPrefix: *constantPrefix,
+		FunctionPrefix: *functionPrefix,
+		Include:        splitPatterns(*include),
+		Exclude:        splitPatterns(*exclude),
+		IdiomaticNames: *idiomaticNames,
+
+		TypedClassArguments: *typedClassArgs,
+		Interfaces:          *interfaces,
+		Fakes:               *fakes,
+		TypeScript:          *typeScript,
+	}
+
+	if *renames != "" {
+		options.Renames, err = generator.LoadRenames(*renames)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// When writing to another directory, the package of $GOFILE is most
+	// likely not the package of the generated code.
+	if options.PackageName == "" && *outputDir != "." {
+		options.PackageName = filepath.Base(dir)
+	}
+
+	describe := *description || *typeScript
+	if !describe && options.PackageName == "" && fileName == "" {
+		log.Fatal("No package name given and $GOFILE is not set, use the -package flag or run the generator with go:generate")
 	}
 
 	wasmData, err := os.ReadFile(*wasm)
@@ -48,7 +202,23 @@ func main() {
 		log.Fatal(err)
 	}
 
-	err = generator.Generate(dir, fileName, wasmData, *initFunction)
+	if describe {
+		if *verbose {
+			log.Printf("Generating description for %s into %s", *wasm, dir)
+		}
+
+		err = generator.GenerateDescription(wasmData, options)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *verbose {
+		log.Printf("Generating code for %s into %s", *wasm, dir)
+	}
+
+	err = generator.Generate(wasmData, options)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -7,7 +7,6 @@ require (
 	github.com/onsi/gomega v1.34.1
 	github.com/tetratelabs/wazero v1.7.3
 	golang.org/x/text v0.17.0
-	golang.org/x/tools v0.24.0
 )
 
 require (
@@ -16,9 +15,8 @@ require (
 	github.com/google/go-cmp v0.6.0 // indirect
 	github.com/google/pprof v0.0.0-20240424215950-a892ee059fd6 // indirect
 	golang.org/x/exp v0.0.0-20240719175910-8a7402abbf56 // indirect
-	golang.org/x/mod v0.20.0 // indirect
 	golang.org/x/net v0.28.0 // indirect
-	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.23.0 // indirect
+	golang.org/x/tools v0.24.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
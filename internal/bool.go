@@ -92,7 +92,10 @@ func (bt *boolType) ToWireType(ctx context.Context, mod api.Module, destructors
 		return api.EncodeI32(bt.falseVal), nil
 	}
 
-	// @todo: implement nil pointer check
+	// A nil pointer is false, like null is in JavaScript.
+	if isNilPointer(o) {
+		return api.EncodeI32(bt.falseVal), nil
+	}
 
 	// Any other type could be considered true?
 	return api.EncodeI32(bt.trueVal), nil
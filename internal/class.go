@@ -142,6 +142,11 @@ func (erc *classType) deleteLater(ctx context.Context, handle IClassBase) (IClas
 
 	registeredPtrTypeRecord.deleteScheduled = true
 
+	err := e.scheduledForDeletion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return handle, nil
 }
 
@@ -180,6 +185,10 @@ func (erc *classType) clone(ctx context.Context, from IClassBase) (IClassBase, e
 
 	if registeredPtrTypeRecord.preservePointerOnDelete {
 		registeredPtrTypeRecord.count.value += 1
+		from.getEngine().trackClassHandle(from)
+		if scope := GetScopeFromContext(ctx); scope != nil {
+			scope.record(from)
+		}
 		return from, nil
 	}
 
@@ -193,6 +202,132 @@ func (erc *classType) clone(ctx context.Context, from IClassBase) (IClassBase, e
 	return clone, nil
 }
 
+// cast returns a new handle of the class to the instance of the given handle.
+// The class must be a base class or a derived class of the class of the
+// handle. The new handle shares the ownership of the instance, like a clone.
+// Downcasts are checked against the dynamic type of the instance.
+func (erc *classType) cast(ctx context.Context, from IClassBase) (IClassBase, error) {
+	registeredPtrTypeRecord := from.getRegisteredPtrTypeRecord()
+	if registeredPtrTypeRecord.ptr == 0 {
+		return nil, fmt.Errorf("class handle already deleted")
+	}
+
+	e := from.getEngine()
+	fromClass := registeredPtrTypeRecord.ptrType.registeredClass
+
+	var ptr uint32
+	var err error
+	if fromClass.isOrDerivesFrom(erc) {
+		ptr, err = e.upcastPointer(ctx, registeredPtrTypeRecord.ptr, fromClass, erc)
+		if err != nil {
+			return nil, err
+		}
+	} else if erc.isOrDerivesFrom(fromClass) {
+		res, err := fromClass.getActualType.Call(ctx, api.EncodeU32(registeredPtrTypeRecord.ptr))
+		if err != nil {
+			return nil, err
+		}
+
+		actualType, ok := e.registeredPointers[api.DecodeI32(res[0])]
+		if !ok || !actualType.pointerType.registeredClass.isOrDerivesFrom(erc) {
+			actualTypeName := "an unknown type"
+			if ok {
+				actualTypeName = actualType.pointerType.registeredClass.name
+			}
+			return nil, fmt.Errorf("cannot downcast %s to %s, the instance is %s", fromClass.name, erc.name, actualTypeName)
+		}
+
+		for current := erc; current != fromClass; current = current.baseClass {
+			if current.downcast == nil {
+				return nil, fmt.Errorf("cannot downcast %s to %s, %s is not polymorphic", fromClass.name, erc.name, current.name)
+			}
+		}
+
+		ptr, err = registeredPtrTypeRecord.ptrType.downcastPointer(ctx, registeredPtrTypeRecord.ptr, fromClass, erc)
+		if err != nil {
+			return nil, err
+		}
+
+		if ptr == 0 {
+			return nil, fmt.Errorf("cannot downcast %s to %s", fromClass.name, erc.name)
+		}
+	} else {
+		return nil, fmt.Errorf("cannot cast %s to %s, the classes are not related", fromClass.name, erc.name)
+	}
+
+	registeredPointer, ok := e.registeredPointers[erc.rawType]
+	if !ok {
+		return nil, fmt.Errorf("class %s has no registered pointer types", erc.name)
+	}
+
+	record := registeredPtrTypeRecord.shallowCopyInternalPointer()
+	record.ptr = ptr
+	record.ptrType = registeredPointer.pointerType
+	if registeredPtrTypeRecord.ptrType.isConst {
+		record.ptrType = registeredPointer.constPointerType
+	}
+
+	// The destructor of the original class has to run when the last handle
+	// is deleted, the cast pointer could point to a base class without a
+	// virtual destructor.
+	record.castFrom = registeredPtrTypeRecord.castFrom
+	if record.castFrom == nil {
+		record.castFrom = registeredPtrTypeRecord.shallowCopyInternalPointer()
+	}
+
+	cast, err := erc.getNewInstance(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+
+	cast.getRegisteredPtrTypeRecord().count.value += 1
+	cast.getRegisteredPtrTypeRecord().deleteScheduled = false
+	return cast, nil
+}
+
+// isOrDerivesFrom returns whether the class is the given class or derives
+// from it.
+func (erc *classType) isOrDerivesFrom(class *classType) bool {
+	for current := erc; current != nil; current = current.baseClass {
+		if current == class {
+			return true
+		}
+	}
+	return false
+}
+
+// findMethod looks up the method on the class, and the instance methods on the
+// base classes, like the prototype chain does in JS.
+func (erc *classType) findMethod(name string) (*publicSymbol, bool) {
+	if method, ok := erc.methods[name]; ok {
+		return method, true
+	}
+
+	for baseClass := erc.baseClass; baseClass != nil; baseClass = baseClass.baseClass {
+		if method, ok := baseClass.methods[name]; ok && !method.isStatic {
+			return method, true
+		}
+	}
+
+	return nil, false
+}
+
+// findProperty looks up the property on the class, and the instance
+// properties on the base classes.
+func (erc *classType) findProperty(name string) (*classProperty, bool) {
+	if property, ok := erc.properties[name]; ok {
+		return property, true
+	}
+
+	for baseClass := erc.baseClass; baseClass != nil; baseClass = baseClass.baseClass {
+		if property, ok := baseClass.properties[name]; ok && !property.Static() {
+			return property, true
+		}
+	}
+
+	return nil, false
+}
+
 func (erc *classType) delete(ctx context.Context, handle IClassBase) error {
 	registeredPtrTypeRecord := handle.getRegisteredPtrTypeRecord()
 	if registeredPtrTypeRecord.ptr == 0 {
@@ -222,6 +357,10 @@ func (erc *classType) delete(ctx context.Context, handle IClassBase) error {
 		registeredPtrTypeRecord.ptr = 0
 	}
 
+	if !registeredPtrTypeRecord.preservePointerOnDelete || registeredPtrTypeRecord.count.value == 0 {
+		handle.getEngine().untrackClassHandle(handle)
+	}
+
 	return nil
 }
 
@@ -246,9 +385,19 @@ func (erc *classType) getNewInstance(ctx context.Context, record *registeredPoin
 
 		result := newElem.Interface()
 
+		e.trackClassHandle(result.(IClassBase))
+		if scope := GetScopeFromContext(ctx); scope != nil {
+			scope.record(result.(IClassBase))
+		}
+
 		return result.(IClassBase), nil
 	}
 
+	e.trackClassHandle(classBase)
+	if scope := GetScopeFromContext(ctx); scope != nil {
+		scope.record(classBase)
+	}
+
 	return classBase, nil
 }
 
@@ -264,6 +413,7 @@ func (erc *classType) getDerivedClassesRecursive() []*classType {
 type IClassType interface {
 	Name() string
 	Type() IType
+	BaseClass() IClassType
 	Properties() []IClassTypeProperty
 	StaticProperties() []IClassTypeProperty
 	Constructors() []IClassTypeConstructor
@@ -300,6 +450,13 @@ func (erc *classType) Type() IType {
 	return &exposedType{registeredType: erc}
 }
 
+func (erc *classType) BaseClass() IClassType {
+	if erc.baseClass == nil {
+		return nil
+	}
+	return erc.baseClass
+}
+
 func (erc *classType) Properties() []IClassTypeProperty {
 	properties := make([]IClassTypeProperty, 0)
 
@@ -424,6 +581,10 @@ type ClassBase struct {
 	registeredPtrTypeRecord *registeredPointerTypeRecord
 }
 
+func (ecb *ClassBase) getEngine() *engine {
+	return ecb.engine
+}
+
 func (ecb *ClassBase) getClassType() *classType {
 	return ecb.classType
 }
@@ -464,12 +625,31 @@ func (ecb *ClassBase) IsInstanceDeleted(ctx context.Context, this IClassBase) bo
 	return ecb.classType.isDeleted(ctx, this)
 }
 
+// Pointer returns the address of the instance in the memory of the module,
+// as a pointer to the class of the instance. It returns 0 when the instance
+// has been deleted.
+func (ecb *ClassBase) Pointer() uint32 {
+	if ecb.registeredPtrTypeRecord == nil {
+		return 0
+	}
+	return ecb.registeredPtrTypeRecord.ptr
+}
+
 func (ecb *ClassBase) IsAliasOfInstance(ctx context.Context, this IClassBase, second IClassBase) (bool, error) {
 	return ecb.classType.isAliasOf(ctx, this, second)
 }
 
+func (ecb *ClassBase) CastInstance(ctx context.Context, this IClassBase, className string) (IClassBase, error) {
+	class, ok := ecb.engine.registeredClasses[className]
+	if !ok {
+		return nil, fmt.Errorf("could not find class %s", className)
+	}
+
+	return class.cast(ecb.engine.Attach(ctx), this)
+}
+
 func (ecb *ClassBase) CallInstanceMethod(ctx context.Context, this any, name string, arguments ...any) (any, error) {
-	method, ok := ecb.classType.methods[name]
+	method, ok := ecb.classType.findMethod(name)
 	if !ok {
 		return nil, fmt.Errorf("%s.%s() is not found", ecb.classType.name, name)
 	}
@@ -482,11 +662,13 @@ func (ecb *ClassBase) CallInstanceMethod(ctx context.Context, this any, name str
 		return nil, fmt.Errorf("%s.%s() is static", ecb.classType.name, name)
 	}
 
-	return method.fn(ctx, this, arguments...)
+	return ecb.engine.observeCall(ctx, name, ecb.classType.name, arguments, func() (any, error) {
+		return method.fn(ctx, this, arguments...)
+	})
 }
 
 func (ecb *ClassBase) SetInstanceProperty(ctx context.Context, this any, name string, value any) error {
-	property, ok := ecb.classType.properties[name]
+	property, ok := ecb.classType.findProperty(name)
 	if !ok {
 		return fmt.Errorf("%s.%s is a not found", ecb.classType.name, name)
 	}
@@ -503,11 +685,14 @@ func (ecb *ClassBase) SetInstanceProperty(ctx context.Context, this any, name st
 		return fmt.Errorf("%s.%s is a read-only property", ecb.classType.name, name)
 	}
 
-	return property.set(ctx, this, value)
+	_, err := ecb.engine.trackCall(ctx, func() (any, error) {
+		return nil, property.set(ctx, this, value)
+	})
+	return err
 }
 
 func (ecb *ClassBase) GetInstanceProperty(ctx context.Context, this any, name string) (any, error) {
-	property, ok := ecb.classType.properties[name]
+	property, ok := ecb.classType.findProperty(name)
 	if !ok {
 		return nil, fmt.Errorf("%s.%s is a not found", ecb.classType.name, name)
 	}
@@ -520,7 +705,9 @@ func (ecb *ClassBase) GetInstanceProperty(ctx context.Context, this any, name st
 		return nil, fmt.Errorf("%s.%s is static", ecb.classType.name, name)
 	}
 
-	return property.get(ctx, this)
+	return ecb.engine.trackCall(ctx, func() (any, error) {
+		return property.get(ctx, this)
+	})
 }
 
 func (ecb *ClassBase) DeleteInheritedInstance(ctx context.Context) error {
@@ -533,6 +720,7 @@ func (ecb *ClassBase) DeleteInheritedInstance(ctx context.Context) error {
 }
 
 type IClassBase interface {
+	getEngine() *engine
 	getClassType() *classType
 	getPtr() uint32
 	getPtrType() *registeredPointerType
@@ -542,7 +730,9 @@ type IClassBase interface {
 	DeleteInstance(ctx context.Context, this IClassBase) error
 	DeleteInstanceLater(ctx context.Context, this IClassBase) (IClassBase, error)
 	IsInstanceDeleted(ctx context.Context, this IClassBase) bool
+	Pointer() uint32
 	IsAliasOfInstance(ctx context.Context, this IClassBase, second IClassBase) (bool, error)
+	CastInstance(ctx context.Context, this IClassBase, className string) (IClassBase, error)
 	CallInstanceMethod(ctx context.Context, this any, name string, arguments ...any) (any, error)
 	SetInstanceProperty(ctx context.Context, this any, name string, value any) error
 	GetInstanceProperty(ctx context.Context, this any, name string) (any, error)
@@ -1117,7 +1307,16 @@ var RegisterClassClassProperty = api.GoModuleFunc(func(ctx context.Context, mod
 					if err != nil {
 						return nil, err
 					}
-					return fieldType.FromWireType(ctx, engine.mod, res[0])
+					value, err := fieldType.FromWireType(ctx, engine.mod, res[0])
+					if err != nil {
+						return nil, err
+					}
+
+					err = engine.checkNullPointer(humanName, fieldType, value)
+					if err != nil {
+						return nil, err
+					}
+					return value, nil
 				},
 				enumerable: true,
 				readOnly:   true,
@@ -1249,7 +1448,17 @@ var RegisterClassProperty = api.GoModuleFunc(func(ctx context.Context, mod api.M
 					if err != nil {
 						return nil, err
 					}
-					return getterReturnType.FromWireType(ctx, engine.mod, res[0])
+
+					value, err := getterReturnType.FromWireType(ctx, engine.mod, res[0])
+					if err != nil {
+						return nil, err
+					}
+
+					err = engine.checkNullPointer(humanName+" getter", getterReturnType, value)
+					if err != nil {
+						return nil, err
+					}
+					return value, nil
 				},
 				enumerable: true,
 				readOnly:   true,
@@ -1361,6 +1570,11 @@ var RegisterSmartPtr = api.GoModuleFunc(func(ctx context.Context, mod api.Module
 		panic(fmt.Errorf("could not read rawDestructor: %w", err))
 	}
 
+	typeName, err := engine.getTypeName(ctx, rawType)
+	if err != nil {
+		panic(fmt.Errorf("could not get type name: %w", err))
+	}
+
 	err = engine.whenDependentTypesAreResolved([]int32{rawType}, []int32{rawPointeeType}, func(types []registeredType) ([]registeredType, error) {
 		pointeeType := types[0]
 
@@ -1380,6 +1594,7 @@ var RegisterSmartPtr = api.GoModuleFunc(func(ctx context.Context, mod api.Module
 			rawShare:        rawShareFunc,
 			rawDestructor:   rawDestructorFunc,
 			specialShare:    specialShare,
+			isStdSharedPtr:  stdSharedPtrTypeName.MatchString(typeName),
 		}
 
 		return []registeredType{smartPointerType}, nil
@@ -1479,6 +1694,9 @@ var CreateInheritingConstructor = api.GoModuleFunc(func(ctx context.Context, mod
 			return nil, err
 		}
 
+		// The Go implementation now owns the record of the inner handle.
+		engine.trackClassHandle(resultClassBase)
+
 		return result, nil
 	}, nil)
 	if err != nil {
@@ -1508,7 +1726,9 @@ func (e *engine) CallStaticClassMethod(ctx context.Context, className, name stri
 	}
 
 	ctx = e.Attach(ctx)
-	res, err := e.registeredClasses[className].methods[name].fn(ctx, nil, arguments...)
+	res, err := e.observeCall(ctx, name, className, arguments, func() (any, error) {
+		return e.registeredClasses[className].methods[name].fn(ctx, nil, arguments...)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error while calling embind function %s on class %s: %w", name, className, err)
 	}
@@ -1532,7 +1752,9 @@ func (e *engine) GetStaticClassProperty(ctx context.Context, className, name str
 	}
 
 	ctx = e.Attach(ctx)
-	res, err := e.registeredClasses[className].properties[name].get(ctx, nil)
+	res, err := e.trackCall(ctx, func() (any, error) {
+		return e.registeredClasses[className].properties[name].get(ctx, nil)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error while calling embind property getter %s on class %s: %w", name, className, err)
 	}
@@ -1560,10 +1782,49 @@ func (e *engine) SetStaticClassProperty(ctx context.Context, className, name str
 	}
 
 	ctx = e.Attach(ctx)
-	err := e.registeredClasses[className].properties[name].set(ctx, nil, value)
+	_, err := e.trackCall(ctx, func() (any, error) {
+		return nil, e.registeredClasses[className].properties[name].set(ctx, nil, value)
+	})
 	if err != nil {
 		return fmt.Errorf("error while calling embind property setter %s on class %s: %w", name, className, err)
 	}
 
 	return nil
 }
+
+// WrapPointer returns a class instance for the address of an instance of the
+// class className in the memory of the module. Like pointers that are
+// returned by C++, the instance is downcast to its dynamic type when the
+// class is polymorphic. When owned is true, deleting the last handle to the
+// instance deletes the instance in C++, otherwise C++ keeps owning it.
+func (e *engine) WrapPointer(ctx context.Context, className string, ptr uint32, owned bool) (IClassBase, error) {
+	class, ok := e.registeredClasses[className]
+	if !ok || !class.hasCppClass {
+		return nil, fmt.Errorf("could not find class %s", className)
+	}
+
+	if ptr == 0 {
+		return nil, nil
+	}
+
+	registeredPointer, ok := e.registeredPointers[class.rawType]
+	if !ok {
+		return nil, fmt.Errorf("class %s has no registered pointer types", className)
+	}
+
+	res, err := registeredPointer.pointerType.FromWireType(e.Attach(ctx), e.mod, api.EncodeU32(ptr))
+	if err != nil {
+		return nil, err
+	}
+
+	handle := res.(IClassBase)
+
+	// Instances that are implemented in Go are always owned by their Go
+	// implementation.
+	record := handle.getRegisteredPtrTypeRecord()
+	if !owned && !record.preservePointerOnDelete {
+		record.borrowed = true
+	}
+
+	return handle, nil
+}
@@ -20,6 +20,8 @@ type IEngine interface {
 	GetEnums() []IEnumType
 	RegisterClass(name string, class any) error
 	GetClasses() []IClassType
+	WrapPointer(ctx context.Context, className string, ptr uint32, owned bool) (IClassBase, error)
+	GetUserTypes() []IType
 	CallStaticClassMethod(ctx context.Context, className, name string, arguments ...any) (any, error)
 	GetStaticClassProperty(ctx context.Context, className, name string) (any, error)
 	SetStaticClassProperty(ctx context.Context, className, name string, value any) error
@@ -31,14 +33,87 @@ type IEngine interface {
 	GetLiveInheritedInstances() []IClassBase
 	FlushPendingDeletes(ctx context.Context) error
 	SetDelayFunction(fn DelayFunction) error
+	LeakReport() *LeakReport
+
+	// Alloc allocates size bytes in the memory of the module with malloc.
+	// When the context contains a scope, the memory is freed when the scope
+	// is closed.
+	Alloc(ctx context.Context, size uint32) (uint32, error)
+
+	// Free frees memory that has been allocated with Alloc.
+	Free(ctx context.Context, ptr uint32) error
+
+	// WriteBytes writes data to the memory of the module at ptr.
+	WriteBytes(ptr uint32, data []byte) error
+
+	// ReadBytes returns a copy of size bytes of the memory of the module at
+	// ptr.
+	ReadBytes(ptr uint32, size uint32) ([]byte, error)
+
+	// ViewBytes returns size bytes of the memory of the module at ptr without
+	// copying them. The returned slice is only valid until the memory of the
+	// module grows, which can happen on every call into the module.
+	ViewBytes(ptr uint32, size uint32) ([]byte, error)
 }
 
 type DelayFunction func(func(ctx context.Context) error) error
 
 type IEngineConfig interface {
+	// WithObserver sets an observer that gets notified about every call that
+	// crosses the embind boundary.
+	WithObserver(observer IObserver) IEngineConfig
+
+	// WithCaptureHandleStacks makes the engine capture the stack trace of
+	// the creation of every class and emval handle, so that LeakReport can
+	// tell where a leaked handle was created. This is expensive, so only
+	// enable this while debugging or testing.
+	WithCaptureHandleStacks(capture bool) IEngineConfig
+
+	// WithFlushPolicy sets when the class handles that are scheduled for
+	// deletion with DeleteInstanceLater are deleted.
+	WithFlushPolicy(policy FlushPolicy) IEngineConfig
+
+	// WithNullPointerErrors makes functions, methods and property getters
+	// that return a null pointer to a class return ErrNullPointer, instead of
+	// a nil value. Without it, the generated wrappers return (nil, nil) for
+	// a null pointer.
+	WithNullPointerErrors(enabled bool) IEngineConfig
 }
 
 type EngineConfig struct {
+	observer            IObserver
+	captureHandleStacks bool
+	flushPolicy         FlushPolicy
+	nullPointerErrors   bool
+}
+
+func (c *EngineConfig) clone() *EngineConfig {
+	ret := *c
+	return &ret
+}
+
+func (c *EngineConfig) WithObserver(observer IObserver) IEngineConfig {
+	ret := c.clone()
+	ret.observer = observer
+	return ret
+}
+
+func (c *EngineConfig) WithCaptureHandleStacks(capture bool) IEngineConfig {
+	ret := c.clone()
+	ret.captureHandleStacks = capture
+	return ret
+}
+
+func (c *EngineConfig) WithFlushPolicy(policy FlushPolicy) IEngineConfig {
+	ret := c.clone()
+	ret.flushPolicy = policy
+	return ret
+}
+
+func (c *EngineConfig) WithNullPointerErrors(enabled bool) IEngineConfig {
+	ret := c.clone()
+	ret.nullPointerErrors = enabled
+	return ret
 }
 
 func GetEngineFromContext(ctx context.Context) (IEngine, error) {
@@ -87,23 +162,35 @@ type EngineKey struct{}
 // Be sure to attach it before you run InstantiateModule on the runtime, unless
 // you run the _start/_initialize function manually.
 func CreateEngine(config IEngineConfig) IEngine {
+	engineConfig, ok := config.(*EngineConfig)
+	if !ok || engineConfig == nil {
+		engineConfig = &EngineConfig{}
+	}
+
 	return &engine{
 		config:               config,
+		observer:             engineConfig.observer,
 		publicSymbols:        map[string]*publicSymbol{},
 		registeredTypes:      map[int32]registeredType{},
 		typeDependencies:     map[int32][]int32{},
 		awaitingDependencies: map[int32][]*awaitingDependency{},
 		registeredConstants:  map[string]*registeredConstant{},
 		registeredEnums:      map[string]*enumType{},
+		registeredUserTypes:  map[string]*userType{},
 		registeredClasses:    map[string]*classType{},
 		registeredClassTypes: map[reflect.Type]*classType{},
 		registeredPointers:   map[int32]*registeredPointer{},
 		registeredTuples:     map[int32]*registeredTuple{},
 		registeredObjects:    map[int32]*registeredObject{},
 		registeredInstances:  map[uint32]IClassBase{},
+		liveClassHandles:     map[*registeredPointerTypeRecord]*liveClassHandle{},
+		allocations:          map[uint32]*allocation{},
+		captureHandleStacks:  engineConfig.captureHandleStacks,
 		deletionQueue:        []IClassBase{},
 		delayFunction:        nil,
-		emvalEngine:          createEmvalEngine(),
+		flushPolicy:          engineConfig.flushPolicy,
+		nullPointerErrors:    engineConfig.nullPointerErrors,
+		emvalEngine:          createEmvalEngine(engineConfig.captureHandleStacks),
 	}
 }
 
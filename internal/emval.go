@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/jerbob92/wazero-emscripten-embind/types"
@@ -65,12 +66,14 @@ func (et *emvalType) GoType() string {
 type emvalHandle struct {
 	value    any
 	refCount int
+	stack    string
 }
 
 type emvalAllocator struct {
-	allocated []*emvalHandle
-	freelist  []int32
-	reserved  int
+	allocated     []*emvalHandle
+	freelist      []int32
+	reserved      int
+	captureStacks bool
 }
 
 func (ea *emvalAllocator) get(id int32) (*emvalHandle, error) {
@@ -95,6 +98,7 @@ func (ea *emvalAllocator) has(id int32) bool {
 
 func (ea *emvalAllocator) allocate(handle *emvalHandle) int32 {
 	var id int32
+	handle.stack = captureStack(ea.captureStacks)
 
 	// Reuse items to free when available
 	if len(ea.freelist) > 0 {
@@ -157,11 +161,30 @@ func (ea *emvalAllocator) decref(id int32) error {
 	return nil
 }
 
+// The kinds of Emval method callers, FUNCTION and CONSTRUCTOR are the values
+// of the kind param of _emval_get_method_caller, _emval_create_invoker uses
+// its own values that are mapped with invokerKinds.
+const (
+	emvalCallerKindFunction    int32 = 0
+	emvalCallerKindConstructor int32 = 1
+	emvalCallerKindMethod      int32 = 2
+	emvalCallerKindCast        int32 = 3
+)
+
+// invokerKinds maps the EM_INVOKER_KIND values of _emval_create_invoker to
+// the kinds of Emval method callers.
+var invokerKinds = map[int32]int32{
+	0: emvalCallerKindFunction,
+	1: emvalCallerKindMethod,
+	2: emvalCallerKindConstructor,
+	3: emvalCallerKindCast,
+}
+
 type emvalRegisteredMethod struct {
 	id       int32
 	argTypes []registeredType
 	name     string
-	kind     *int32 // FUNCTION == 0, CONSTRUCTOR == 1
+	kind     *int32
 }
 
 type emvalEngine struct {
@@ -173,7 +196,7 @@ type emvalEngine struct {
 	registeredMethods     map[int32]*emvalRegisteredMethod
 }
 
-func createEmvalEngine() *emvalEngine {
+func createEmvalEngine(captureStacks bool) *emvalEngine {
 	return &emvalEngine{
 		allocator: &emvalAllocator{
 			allocated: []*emvalHandle{
@@ -191,8 +214,9 @@ func createEmvalEngine() *emvalEngine {
 					value: false,
 				},
 			},
-			freelist: []int32{},
-			reserved: 5,
+			freelist:      []int32{},
+			reserved:      5,
+			captureStacks: captureStacks,
 		},
 		globals:             map[string]any{},
 		symbols:             map[uint32]string{},
@@ -284,10 +308,19 @@ func (e *emvalEngine) getElemField(symbol any, field string) (*reflect.Value, er
 	return nil, fmt.Errorf("could not find field \"%s\" by embind_property tag, name or by %s", field, upperFirst)
 }
 
-func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registeredMethod *emvalRegisteredMethod, obj any, methodToCall *reflect.Value, destructorsRef, argsBase uint32, injectCtx bool) (uint64, error) {
-	var err error
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registeredMethod *emvalRegisteredMethod, obj any, methodName string, methodToCall *reflect.Value, destructorsRef, argsBase uint32, injectCtx bool) (_ uint64, err error) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
 	argCount := len(registeredMethod.argTypes)
 	args := make([]any, argCount)
+
+	var result any
+	start := time.Now()
+	defer func() {
+		engine.observeEmvalCall(ctx, obj, methodName, args[1:], result, err, start)
+	}()
+
 	argTypeNames := make([]string, argCount)
 	for i := 1; i < argCount; i++ {
 		args[i], err = registeredMethod.argTypes[i].ReadValueFromPointer(ctx, mod, argsBase)
@@ -300,11 +333,16 @@ func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registered
 	}
 
 	if methodToCall == nil && registeredMethod.kind != nil {
-		if *registeredMethod.kind == 0 {
+		if *registeredMethod.kind == emvalCallerKindFunction {
 			objMethod := reflect.ValueOf(obj)
 			methodToCall = &objMethod
 		}
-		if *registeredMethod.kind == 1 {
+		if *registeredMethod.kind == emvalCallerKindCast {
+			// A cast converts its only argument to the return type.
+			result = args[1]
+			return EmvalReturnValue(ctx, mod, registeredMethod.argTypes[0], destructorsRef, result)
+		}
+		if *registeredMethod.kind == emvalCallerKindConstructor {
 			var res any
 			c, ok := obj.(IEmvalConstructor)
 			if ok {
@@ -370,6 +408,8 @@ func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registered
 				}
 			}
 
+			result = res
+
 			newRes, err := EmvalReturnValue(ctx, mod, registeredMethod.argTypes[0], destructorsRef, res)
 			if err != nil {
 				return 0, fmt.Errorf("could not call EmvalReturnValue on response")
@@ -398,6 +438,10 @@ func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registered
 	if injectCtx {
 		callArgs = make([]reflect.Value, 1)
 		callArgs[0] = reflect.ValueOf(ctx)
+	} else if methodType := methodToCall.Type(); methodType.NumIn() == len(callArgs)+1 && methodType.In(0) == contextType {
+		// Methods that take a context as first argument get the context of
+		// the call, this allows them to call back into the module.
+		callArgs = append([]reflect.Value{reflect.ValueOf(ctx)}, callArgs...)
 	}
 
 	resultData := methodToCall.Call(callArgs)
@@ -446,6 +490,7 @@ func (e *emvalEngine) callMethod(ctx context.Context, mod api.Module, registered
 	}
 
 	rv := resultData[0].Interface()
+	result = rv
 	res, err := EmvalReturnValue(ctx, mod, registeredMethod.argTypes[0], destructorsRef, rv)
 	if err != nil {
 		return 0, fmt.Errorf("could not call EmvalReturnValue on response")
@@ -866,6 +911,49 @@ var EmvalRunDestructors = api.GoModuleFunc(func(ctx context.Context, mod api.Mod
 	}
 })
 
+// getMethodCaller returns the ID of the method caller for the given argument
+// types and kind, and registers it when it does not exist yet.
+func (e *engine) getMethodCaller(ctx context.Context, mod api.Module, argCount int, argsTypeBase uint32, kind *int32) (int32, error) {
+	typeNames := make([]string, argCount)
+	argTypes := make([]registeredType, argCount)
+	for i := 0; i < argCount; i++ {
+		argType, ok := mod.Memory().ReadUint32Le(argsTypeBase + (4 * uint32(i)))
+		if !ok {
+			return 0, fmt.Errorf("could not read arg type for arg %d from memory", i)
+		}
+
+		registeredType, err := e.requireRegisteredType(ctx, int32(argType), fmt.Sprintf("argument %d", i))
+		if err != nil {
+			return 0, fmt.Errorf("could not require registered type: %w", err)
+		}
+
+		typeNames[i] = registeredType.Name()
+		argTypes[i] = registeredType
+	}
+
+	signatureName := typeNames[0] + "_$" + strings.Join(typeNames[1:], "_") + "$"
+	if kind != nil {
+		signatureName += strconv.Itoa(int(*kind))
+	}
+
+	id, ok := e.emvalEngine.registeredMethodIds[signatureName]
+	if ok {
+		return id, nil
+	}
+
+	newID := e.emvalEngine.registeredMethodCount
+	e.emvalEngine.registeredMethodIds[signatureName] = newID
+	e.emvalEngine.registeredMethods[newID] = &emvalRegisteredMethod{
+		id:       newID,
+		argTypes: argTypes,
+		name:     signatureName,
+		kind:     kind,
+	}
+	e.emvalEngine.registeredMethodCount++
+
+	return newID, nil
+}
+
 var EmvalGetMethodCaller = func(hasKind bool) api.GoModuleFunc {
 	return api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
 		engine := MustGetEngineFromContext(ctx, mod).(*engine)
@@ -873,51 +961,177 @@ var EmvalGetMethodCaller = func(hasKind bool) api.GoModuleFunc {
 		argCount := int(api.DecodeI32(stack[0]))
 		argsTypeBase := uint32(api.DecodeI32(stack[1]))
 
-		typeNames := make([]string, argCount)
-		argTypes := make([]registeredType, argCount)
-		for i := 0; i < argCount; i++ {
-			argType, ok := mod.Memory().ReadUint32Le(argsTypeBase + (4 * uint32(i)))
-			if !ok {
-				panic(fmt.Errorf("could not read arg type for arg %d from memory", i))
-			}
-
-			registeredType, err := engine.requireRegisteredType(ctx, int32(argType), fmt.Sprintf("argument %d", i))
-			if err != nil {
-				panic(fmt.Errorf("could not require registered type: %w", err))
-			}
+		var kind *int32
+		if hasKind {
+			decodedKind := api.DecodeI32(stack[2])
+			kind = &decodedKind
+		}
 
-			typeNames[i] = registeredType.Name()
-			argTypes[i] = registeredType
+		id, err := engine.getMethodCaller(ctx, mod, argCount, argsTypeBase, kind)
+		if err != nil {
+			panic(err)
 		}
 
-		signatureName := typeNames[0] + "_$" + strings.Join(typeNames[1:], "_") + "$"
+		stack[0] = api.EncodeI32(id)
+	})
+}
 
-		id, ok := engine.emvalEngine.registeredMethodIds[signatureName]
-		if ok {
-			stack[0] = api.EncodeI32(id)
-			return
+// EmvalCreateInvoker replaces _emval_get_method_caller in newer Emscripten
+// versions, the kind param also tells whether the invoker calls a method or
+// casts a value.
+var EmvalCreateInvoker = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+
+	argCount := int(api.DecodeI32(stack[0]))
+	argsTypeBase := uint32(api.DecodeI32(stack[1]))
+
+	kind, ok := invokerKinds[api.DecodeI32(stack[2])]
+	if !ok {
+		panic(fmt.Errorf("unknown invoker kind %d", api.DecodeI32(stack[2])))
+	}
+
+	id, err := engine.getMethodCaller(ctx, mod, argCount, argsTypeBase, &kind)
+	if err != nil {
+		panic(err)
+	}
+
+	stack[0] = api.EncodeI32(id)
+})
+
+// EmvalInvoke replaces _emval_call and _emval_call_method in newer
+// Emscripten versions, together with EmvalCreateInvoker.
+var EmvalInvoke = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+	caller := api.DecodeI32(stack[0])
+
+	registeredMethod, ok := engine.emvalEngine.registeredMethods[caller]
+	if !ok || registeredMethod.kind == nil {
+		panic(fmt.Errorf("could not call invoker with ID %d", caller))
+	}
+
+	destructorsRef := uint32(api.DecodeI32(stack[3]))
+	argsBase := uint32(api.DecodeI32(stack[4]))
+
+	// Casts only use their argument.
+	var handle any
+	if *registeredMethod.kind != emvalCallerKindCast {
+		var err error
+		handle, err = engine.emvalEngine.toValue(api.DecodeI32(stack[1]))
+		if err != nil {
+			panic(fmt.Errorf("could not find handle: %w", err))
 		}
+	}
 
-		newID := engine.emvalEngine.registeredMethodCount
-		newRegisteredMethod := &emvalRegisteredMethod{
-			id:       newID,
-			argTypes: argTypes,
-			name:     signatureName,
+	var method *reflect.Value
+	var injectCtx bool
+	methodName := ""
+	switch *registeredMethod.kind {
+	case emvalCallerKindMethod:
+		var err error
+		methodName, err = engine.getStringOrSymbol(uint32(api.DecodeI32(stack[2])))
+		if err != nil {
+			panic(fmt.Errorf("could not get symbol name"))
 		}
 
-		if hasKind {
-			kind := api.DecodeI32(stack[2])
-			newRegisteredMethod.kind = &kind
+		method, injectCtx, err = EmvalGetMethodOnObject(handle, registeredMethod, methodName)
+		if err != nil {
+			panic(fmt.Errorf("could not call %s on %T: %w", methodName, handle, err))
 		}
+	case emvalCallerKindConstructor:
+		methodName = "new"
+	}
 
-		engine.emvalEngine.registeredMethodIds[signatureName] = newID
-		engine.emvalEngine.registeredMethods[newID] = newRegisteredMethod
-		engine.emvalEngine.registeredMethodCount++
+	res, err := engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, methodName, method, destructorsRef, argsBase, injectCtx)
+	if err != nil {
+		panic(fmt.Errorf("could not call %s on %T: %w", registeredMethod.name, handle, err))
+	}
+	stack[0] = api.EncodeF64(float64(res))
+})
 
-		stack[0] = api.EncodeI32(newID)
-		return
+// EmvalFromCurrentCxaException returns a handle to the C++ exception that is
+// currently being handled. Go can not inspect the exception, so the handle
+// contains ErrCppException.
+var EmvalFromCurrentCxaException = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+	stack[0] = api.EncodeI32(engine.emvalEngine.toHandle(ErrCppException))
+})
+
+// EmvalCoroSuspend is called when a C++ coroutine awaits a value. When the
+// value is a Promise, the coroutine is resumed once the promise is settled,
+// any other value resumes the coroutine right away, like await in JS. The
+// resume is always queued, so that the coroutine never continues while this
+// import is still running.
+var EmvalCoroSuspend = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+
+	value, err := engine.emvalEngine.toValue(api.DecodeI32(stack[0]))
+	if err != nil {
+		panic(fmt.Errorf("could not find handle: %w", err))
+	}
+	awaiterPtr := stack[1]
+
+	resume := func(ctx context.Context, value any, err error) error {
+		return engine.queueCoroResume(ctx, func(ctx context.Context) error {
+			resumeFunction := "_emval_coro_resume"
+			if err != nil {
+				// Newer Emscripten versions export _emval_coro_reject to make
+				// the awaiter throw the rejection reason, older versions never
+				// resume the coroutine on a rejection.
+				resumeFunction = "_emval_coro_reject"
+				value = err
+			}
+
+			coroResume := mod.ExportedFunction(resumeFunction)
+			if coroResume == nil {
+				if err != nil {
+					return fmt.Errorf("awaited promise was rejected: %w", err)
+				}
+				return fmt.Errorf("%s is not exported", resumeFunction)
+			}
+
+			_, callErr := coroResume.Call(ctx, awaiterPtr, api.EncodeI32(engine.emvalEngine.toHandle(value)))
+			return callErr
+		})
+	}
+
+	// Prevent the resume from running inside this import when the value is
+	// not a promise or when the promise has already been settled.
+	_, err = engine.trackCallDepth(func() (any, error) {
+		promise, ok := value.(*Promise)
+		if !ok {
+			return nil, resume(ctx, value, nil)
+		}
+		return nil, promise.Then(ctx, resume)
 	})
-}
+	if err != nil {
+		panic(fmt.Errorf("could not resume coroutine: %w", err))
+	}
+})
+
+// EmvalCoroMakePromise creates the Promise that a C++ coroutine returns, and
+// writes handles to the functions that settle it to the given pointers.
+var EmvalCoroMakePromise = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+	resolveHandlePtr := uint32(api.DecodeI32(stack[0]))
+	rejectHandlePtr := uint32(api.DecodeI32(stack[1]))
+
+	promise := NewPromise()
+	resolve := func(ctx context.Context, value any) error {
+		return promise.Resolve(ctx, value)
+	}
+	rejectWithCurrentException := func(ctx context.Context) error {
+		return promise.Reject(ctx, ErrCppException)
+	}
+
+	if !mod.Memory().WriteUint32Le(resolveHandlePtr, uint32(engine.emvalEngine.toHandle(resolve))) {
+		panic(fmt.Errorf("could not write resolve handle to memory"))
+	}
+	if !mod.Memory().WriteUint32Le(rejectHandlePtr, uint32(engine.emvalEngine.toHandle(rejectWithCurrentException))) {
+		panic(fmt.Errorf("could not write reject handle to memory"))
+	}
+
+	stack[0] = api.EncodeI32(engine.emvalEngine.toHandle(promise))
+})
 
 var EmvalCall = func(hasF64Return bool) api.GoModuleFunc {
 	return api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
@@ -938,7 +1152,12 @@ var EmvalCall = func(hasF64Return bool) api.GoModuleFunc {
 				panic(fmt.Errorf("could not call method with ID %d", caller))
 			}
 
-			res, err := engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, nil, destructorsRef, argsBase, false)
+			methodName := ""
+			if registeredMethod.kind != nil && *registeredMethod.kind == emvalCallerKindConstructor {
+				methodName = "new"
+			}
+
+			res, err := engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, methodName, nil, destructorsRef, argsBase, false)
 			if err != nil {
 				panic(fmt.Errorf("could not call %s on %T: %w", registeredMethod.name, handle, err))
 			}
@@ -975,6 +1194,7 @@ var EmvalCall = func(hasF64Return bool) api.GoModuleFunc {
 				reflectValues[i] = reflect.ValueOf(args[i])
 			}
 
+			start := time.Now()
 			value := reflect.ValueOf(handle)
 			result := value.Call(reflectValues)
 
@@ -983,6 +1203,8 @@ var EmvalCall = func(hasF64Return bool) api.GoModuleFunc {
 				resultVal = result[0].Interface()
 			}
 
+			engine.observeEmvalCall(ctx, handle, "", args, resultVal, nil, start)
+
 			newHandle := engine.emvalEngine.toHandle(resultVal)
 			stack[0] = api.EncodeI32(newHandle)
 		}
@@ -1084,7 +1306,7 @@ var EmvalCallMethod = api.GoModuleFunc(func(ctx context.Context, mod api.Module,
 		panic(fmt.Errorf("could not call %s on %T: %w", methodName, handle, err))
 	}
 
-	res, err := engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, method, destructorsRef, argsBase, injectCtx)
+	res, err := engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, methodName, method, destructorsRef, argsBase, injectCtx)
 	if err != nil {
 		panic(fmt.Errorf("could not call %s on %T: %w", methodName, handle, err))
 	}
@@ -1120,7 +1342,7 @@ var EmvalCallVoidMethod = api.GoModuleFunc(func(ctx context.Context, mod api.Mod
 		panic(fmt.Errorf("could not call %s on %T: %w", methodName, handle, err))
 	}
 
-	_, err = engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, method, 0, argsBase, injectCtx)
+	_, err = engine.emvalEngine.callMethod(ctx, mod, registeredMethod, handle, methodName, method, 0, argsBase, injectCtx)
 	if err != nil {
 		panic(fmt.Errorf("could not call %s on %T: %w", methodName, handle, err))
 	}
@@ -9,13 +9,16 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/experimental/table"
+	"github.com/tetratelabs/wazero/sys"
 )
 
 type engine struct {
 	config               IEngineConfig
+	observer             IObserver
 	mod                  api.Module
 	publicSymbols        map[string]*publicSymbol
 	registeredTypes      map[int32]registeredType
@@ -23,14 +26,24 @@ type engine struct {
 	awaitingDependencies map[int32][]*awaitingDependency
 	registeredConstants  map[string]*registeredConstant
 	registeredEnums      map[string]*enumType
+	registeredUserTypes  map[string]*userType
 	registeredPointers   map[int32]*registeredPointer
 	registeredClasses    map[string]*classType
 	registeredClassTypes map[reflect.Type]*classType
 	registeredTuples     map[int32]*registeredTuple
 	registeredObjects    map[int32]*registeredObject
 	registeredInstances  map[uint32]IClassBase
+	liveClassHandles     map[*registeredPointerTypeRecord]*liveClassHandle
+	allocations          map[uint32]*allocation
+	captureHandleStacks  bool
 	deletionQueue        []IClassBase
 	delayFunction        DelayFunction
+	coroResumes          []func(ctx context.Context) error
+	coroResumesScheduled bool
+	flushPolicy          FlushPolicy
+	nullPointerErrors    bool
+	flushDeadline        time.Time
+	callDepth            int
 	emvalEngine          *emvalEngine
 }
 
@@ -451,6 +464,15 @@ func (e *engine) craftInvokerFunction(humanName string, argTypes []registeredTyp
 			return nil, fmt.Errorf("function %s called with %d argument(s), expected %d arg(s)", humanName, len(arguments), argCount-2)
 		}
 
+		// Don't enter the guest when the context is already done, the call
+		// would be interrupted before doing anything useful.
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, &InterruptedError{
+				Name: humanName,
+				Err:  ctxErr,
+			}
+		}
+
 		invoker := cppInvokerFunc
 		fn := cppTargetFunc
 		retType := argTypes[0]
@@ -476,7 +498,20 @@ func (e *engine) craftInvokerFunction(humanName string, argTypes []registeredTyp
 		for i := 0; i < argCount-2; i++ {
 			argsWired[i], err = argTypes[i+2].ToWireType(ctx, e.mod, destructors, arguments[i])
 			if err != nil {
-				return nil, fmt.Errorf("could not get wire type of argument %d (%s): %w", i, argTypes[i+2].Name(), err)
+				err = fmt.Errorf("could not get wire type of argument %d (%s): %w", i, argTypes[i+2].Name(), err)
+
+				// Clean up the arguments that were already wired.
+				var destructorErr error
+				if needsDestructorStack {
+					destructorErr = e.runDestructors(detachContext(ctx), *destructors)
+				} else {
+					destructorErr = e.runArgumentDestructors(detachContext(ctx), argTypes[2:i+2], argsWired[:i])
+				}
+				if destructorErr != nil {
+					return nil, fmt.Errorf("%w (could not run destructors: %v)", err, destructorErr)
+				}
+
+				return nil, err
 			}
 		}
 
@@ -486,25 +521,11 @@ func (e *engine) craftInvokerFunction(humanName string, argTypes []registeredTyp
 		}
 		callArgs = append(callArgs, argsWired...)
 
-		res, err := invoker.Call(ctx, callArgs...)
-		if err != nil {
-			return nil, err
-		}
-
-		var returnVal any
-		if returns {
-			returnVal, err = retType.FromWireType(ctx, e.mod, res[0])
-			if err != nil {
-				return nil, fmt.Errorf("could not get wire type of return value (%s) on %T: %w", retType.Name(), retType, err)
+		runDestructors := func(ctx context.Context) error {
+			if needsDestructorStack {
+				return e.runDestructors(ctx, *destructors)
 			}
-		}
 
-		if needsDestructorStack {
-			err = e.runDestructors(ctx, *destructors)
-			if err != nil {
-				return nil, err
-			}
-		} else {
 			// Skip return value at index 0 - it's not deleted here. Also skip class type if not a method.
 			startArg := 2
 			if isClassMethodFunc {
@@ -519,18 +540,160 @@ func (e *engine) craftInvokerFunction(humanName string, argTypes []registeredTyp
 
 				argDestructorFunc := argTypes[i].DestructorFunction(ctx, e.mod, api.DecodeU32(callArgs[ptrIndex]))
 				if argDestructorFunc != nil {
-					err = argDestructorFunc.run(ctx, e.mod)
+					err := argDestructorFunc.run(ctx, e.mod)
 					if err != nil {
-						return nil, err
+						return err
 					}
 				}
 			}
+
+			return nil
+		}
+
+		res, err := invoker.Call(ctx, callArgs...)
+		if err != nil {
+			err = contextError(ctx, humanName, err)
+
+			// The module is closed when the runtime or module has been
+			// configured with WithCloseOnContextDone and the context was
+			// done, there is no memory left to clean up in that case.
+			if !e.mod.IsClosed() {
+				// Use a context that can't be done, otherwise the
+				// destructors would be interrupted too.
+				destructorErr := runDestructors(detachContext(ctx))
+				if destructorErr != nil {
+					return nil, fmt.Errorf("%w (could not run destructors: %v)", err, destructorErr)
+				}
+			}
+
+			return nil, err
+		}
+
+		var returnVal any
+		if returns {
+			returnVal, err = retType.FromWireType(ctx, e.mod, res[0])
+			if err != nil {
+				return nil, fmt.Errorf("could not get wire type of return value (%s) on %T: %w", retType.Name(), retType, err)
+			}
+		}
+
+		err = runDestructors(ctx)
+		if err != nil {
+			return nil, contextError(ctx, humanName, err)
+		}
+
+		if returns {
+			err = e.checkNullPointer(humanName, retType, returnVal)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		return returnVal, nil
 	}
 }
 
+// contextError makes sure that a call that was interrupted because the context
+// was done returns an error that wraps context.Canceled or
+// context.DeadlineExceeded, so that callers can check it with errors.Is.
+// Wazero returns a sys.ExitError when the call was interrupted by
+// WithCloseOnContextDone.
+func contextError(ctx context.Context, humanName string, err error) error {
+	ctxErr := ctx.Err()
+	if ctxErr == nil {
+		var exitErr *sys.ExitError
+		if !errors.As(err, &exitErr) {
+			return err
+		}
+
+		switch exitErr.ExitCode() {
+		case sys.ExitCodeContextCanceled:
+			ctxErr = context.Canceled
+		case sys.ExitCodeDeadlineExceeded:
+			ctxErr = context.DeadlineExceeded
+		default:
+			return err
+		}
+	}
+
+	return &InterruptedError{
+		Name:  humanName,
+		Err:   ctxErr,
+		Cause: err,
+	}
+}
+
+// InterruptedError is returned by calls into embind that were not made or
+// that were interrupted because the context was done. It wraps
+// context.Canceled or context.DeadlineExceeded, so it can be checked with
+// errors.Is.
+type InterruptedError struct {
+	// Name is the name of the function that was called.
+	Name string
+
+	// Err is the error of the context.
+	Err error
+
+	// Cause is the error that was returned by the interrupted call, nil when
+	// the call was not made.
+	Cause error
+}
+
+func (ie *InterruptedError) Error() string {
+	if ie.Cause == nil {
+		return fmt.Sprintf("could not call %s: %v", ie.Name, ie.Err)
+	}
+	return fmt.Sprintf("call to %s was interrupted: %v (%v)", ie.Name, ie.Err, ie.Cause)
+}
+
+func (ie *InterruptedError) Unwrap() []error {
+	if ie.Cause == nil {
+		return []error{ie.Err}
+	}
+	return []error{ie.Err, ie.Cause}
+}
+
+// runArgumentDestructors runs the destructor functions of the given wired
+// arguments, for types that don't need the destructor stack.
+func (e *engine) runArgumentDestructors(ctx context.Context, argTypes []registeredType, argsWired []uint64) error {
+	for i := range argsWired {
+		argDestructorFunc := argTypes[i].DestructorFunction(ctx, e.mod, api.DecodeU32(argsWired[i]))
+		if argDestructorFunc != nil {
+			err := argDestructorFunc.run(ctx, e.mod)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// detachedContext keeps the values of the parent context, but is never done.
+// This is used to run cleanup code after the parent context is done.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (dc detachedContext) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (dc detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (dc detachedContext) Err() error {
+	return nil
+}
+
+func (dc detachedContext) Value(key any) any {
+	return dc.parent.Value(key)
+}
+
+func detachContext(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
 type destructorFunc struct {
 	function    string
 	apiFunction api.Function
@@ -831,6 +994,7 @@ func (e *engine) FlushPendingDeletes(ctx context.Context) error {
 			return err
 		}
 	}
+	e.flushDeadline = time.Time{}
 	return nil
 }
 
@@ -846,5 +1010,12 @@ func (e *engine) SetDelayFunction(fn DelayFunction) error {
 		}
 	}
 
+	if len(e.coroResumes) > 0 && fn != nil && !e.coroResumesScheduled {
+		err := e.scheduleCoroResumes()
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -3,6 +3,7 @@ package embind
 import (
 	"context"
 	"fmt"
+	"reflect"
 
 	"github.com/tetratelabs/wazero/api"
 )
@@ -62,6 +63,12 @@ func (et *enumType) FromWireType(ctx context.Context, mod api.Module, value uint
 func (et *enumType) ToWireType(ctx context.Context, mod api.Module, destructors *[]*destructorFunc, o any) (uint64, error) {
 	val, ok := et.valuesByGoValue[o]
 	if !ok {
+		// Values of the Go enum type are never mapped by their underlying
+		// value, they have to be one of the registered values.
+		if et.registeredInGo && reflect.TypeOf(o) == reflect.TypeOf(et.goValue) {
+			return 0, fmt.Errorf("could not map enum value %v, %d is not a valid value of enum %s", o, o, et.name)
+		}
+
 		val, ok = et.valuesByCppValue[o]
 		if !ok {
 			return 0, fmt.Errorf("could not map enum value %v, enum value not registered as Go or C++ enum", o)
@@ -0,0 +1,139 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type flushPolicyKind int
+
+const (
+	flushManually flushPolicyKind = iota
+	flushAfterCall
+	flushOnQueueSize
+	flushOnActivityAfterInterval
+)
+
+// FlushPolicy decides when the class handles that are scheduled for deletion
+// with DeleteInstanceLater are deleted. The default policy only deletes them
+// when FlushPendingDeletes is called or when the delay function calls the
+// flush function.
+//
+// Pending deletes are never flushed while C++ code is running, they are
+// flushed when the top-level call returns, which mirrors the way Emscripten
+// uses the delay function to flush outside the current call stack.
+type FlushPolicy struct {
+	kind      flushPolicyKind
+	queueSize int
+	interval  time.Duration
+}
+
+// FlushManually returns the default flush policy, pending deletes are only
+// flushed by calling FlushPendingDeletes, or by the delay function.
+func FlushManually() FlushPolicy {
+	return FlushPolicy{kind: flushManually}
+}
+
+// FlushAfterCall returns a flush policy that flushes the pending deletes after
+// every top-level call into embind.
+func FlushAfterCall() FlushPolicy {
+	return FlushPolicy{kind: flushAfterCall}
+}
+
+// FlushOnQueueSize returns a flush policy that flushes the pending deletes as
+// soon as more than size handles are scheduled for deletion.
+func FlushOnQueueSize(size int) FlushPolicy {
+	return FlushPolicy{kind: flushOnQueueSize, queueSize: size}
+}
+
+// FlushOnActivityAfterInterval returns a flush policy that flushes the
+// pending deletes on the first activity after the given interval passed since
+// the first handle was scheduled for deletion: when a top-level call returns
+// or when the next handle is scheduled for deletion. There is no timer, an
+// engine that is not used never flushes, as the engine can only be used from
+// one goroutine at a time.
+func FlushOnActivityAfterInterval(interval time.Duration) FlushPolicy {
+	return FlushPolicy{kind: flushOnActivityAfterInterval, interval: interval}
+}
+
+// trackCall keeps track of the call depth so that queued coroutine resumes
+// only run and pending deletes are only flushed by the flush policy when the
+// top-level call returned.
+func (e *engine) trackCall(ctx context.Context, call func() (any, error)) (any, error) {
+	res, err := e.trackCallDepth(call)
+	if e.callDepth > 0 {
+		return res, err
+	}
+
+	resumeErr := e.runCoroResumes(ctx)
+	if resumeErr != nil && err == nil {
+		return nil, fmt.Errorf("could not resume coroutine: %w", resumeErr)
+	}
+
+	flushErr := e.flushByPolicy(ctx, true)
+	if flushErr != nil && err == nil {
+		return nil, fmt.Errorf("could not flush pending deletes: %w", flushErr)
+	}
+
+	return res, err
+}
+
+// trackCallDepth runs the call with an increased call depth. The depth is
+// restored when the call panics, otherwise the flush policy would never
+// flush again.
+func (e *engine) trackCallDepth(call func() (any, error)) (any, error) {
+	e.callDepth++
+	defer func() {
+		e.callDepth--
+	}()
+
+	return call()
+}
+
+// flushByPolicy flushes the pending deletes when the flush policy says so.
+// It must only be called when no call is running.
+func (e *engine) flushByPolicy(ctx context.Context, afterCall bool) error {
+	if len(e.deletionQueue) == 0 || e.mod == nil || e.mod.IsClosed() {
+		return nil
+	}
+
+	switch e.flushPolicy.kind {
+	case flushAfterCall:
+		if !afterCall {
+			return nil
+		}
+	case flushOnQueueSize:
+		if len(e.deletionQueue) <= e.flushPolicy.queueSize {
+			return nil
+		}
+	case flushOnActivityAfterInterval:
+		if time.Now().Before(e.flushDeadline) {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	// Prevent calls that are made by the destructors from flushing again.
+	e.callDepth++
+	defer func() {
+		e.callDepth--
+	}()
+
+	return e.FlushPendingDeletes(e.Attach(ctx))
+}
+
+// scheduledForDeletion is called after a handle has been added to the
+// deletion queue.
+func (e *engine) scheduledForDeletion(ctx context.Context) error {
+	if e.flushPolicy.kind == flushOnActivityAfterInterval && len(e.deletionQueue) == 1 {
+		e.flushDeadline = time.Now().Add(e.flushPolicy.interval)
+	}
+
+	if e.callDepth > 0 {
+		return nil
+	}
+
+	return e.flushByPolicy(ctx, false)
+}
@@ -0,0 +1,207 @@
+package embind
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strings"
+)
+
+// LeakReport contains the class handles and emval handles that are alive in
+// the engine at the moment the report was made.
+type LeakReport struct {
+	ClassHandles []LiveClassHandle
+	EmvalHandles []LiveEmvalHandle
+}
+
+// LiveClassHandle is a class handle that has not been deleted yet.
+type LiveClassHandle struct {
+	ClassName string
+	Pointer   uint32
+	Handle    IClassBase
+
+	// RefCount is the number of handles that share the instance, like
+	// clones.
+	RefCount int32
+
+	// SmartPointerType is the name of the smart pointer type that holds the
+	// instance, empty when the handle holds a raw pointer.
+	SmartPointerType string
+
+	// Stack is the stack trace of the creation of the handle, only filled
+	// when the engine is configured with WithCaptureHandleStacks(true).
+	Stack string
+}
+
+// LiveEmvalHandle is an emval handle that has not been released yet.
+type LiveEmvalHandle struct {
+	ID       int32
+	Value    any
+	RefCount int
+
+	// Stack is the stack trace of the creation of the handle, only filled
+	// when the engine is configured with WithCaptureHandleStacks(true).
+	Stack string
+}
+
+// Empty returns whether the report does not contain any handles.
+func (lr *LeakReport) Empty() bool {
+	return len(lr.ClassHandles) == 0 && len(lr.EmvalHandles) == 0
+}
+
+// Diff returns a report with the handles that are in this report, but that
+// were not in the given report.
+func (lr *LeakReport) Diff(before *LeakReport) *LeakReport {
+	existingClassHandles := map[IClassBase]bool{}
+	for i := range before.ClassHandles {
+		existingClassHandles[before.ClassHandles[i].Handle] = true
+	}
+
+	existingEmvalHandles := map[int32]any{}
+	for i := range before.EmvalHandles {
+		existingEmvalHandles[before.EmvalHandles[i].ID] = before.EmvalHandles[i].Value
+	}
+
+	diff := &LeakReport{
+		ClassHandles: []LiveClassHandle{},
+		EmvalHandles: []LiveEmvalHandle{},
+	}
+
+	for i := range lr.ClassHandles {
+		if !existingClassHandles[lr.ClassHandles[i].Handle] {
+			diff.ClassHandles = append(diff.ClassHandles, lr.ClassHandles[i])
+		}
+	}
+
+	for i := range lr.EmvalHandles {
+		value, ok := existingEmvalHandles[lr.EmvalHandles[i].ID]
+		if !ok || !isSameEmvalValue(value, lr.EmvalHandles[i].Value) {
+			diff.EmvalHandles = append(diff.EmvalHandles, lr.EmvalHandles[i])
+		}
+	}
+
+	return diff
+}
+
+func isSameEmvalValue(a, b any) (same bool) {
+	// Not all values are comparable, treat those as different values.
+	defer func() {
+		if recover() != nil {
+			same = false
+		}
+	}()
+
+	return a == b
+}
+
+func (lr *LeakReport) String() string {
+	if lr.Empty() {
+		return "no live handles"
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(fmt.Sprintf("%d live class handle(s), %d live emval handle(s)", len(lr.ClassHandles), len(lr.EmvalHandles)))
+	for i := range lr.ClassHandles {
+		builder.WriteString(fmt.Sprintf("\n- class %s, ptr: %d, references: %d", lr.ClassHandles[i].ClassName, lr.ClassHandles[i].Pointer, lr.ClassHandles[i].RefCount))
+		if lr.ClassHandles[i].SmartPointerType != "" {
+			builder.WriteString(", smart pointer: " + lr.ClassHandles[i].SmartPointerType)
+		}
+		if lr.ClassHandles[i].Stack != "" {
+			builder.WriteString(", created at:\n" + lr.ClassHandles[i].Stack)
+		}
+	}
+	for i := range lr.EmvalHandles {
+		builder.WriteString(fmt.Sprintf("\n- emval %d, value: %T, references: %d", lr.EmvalHandles[i].ID, lr.EmvalHandles[i].Value, lr.EmvalHandles[i].RefCount))
+		if lr.EmvalHandles[i].Stack != "" {
+			builder.WriteString(", created at:\n" + lr.EmvalHandles[i].Stack)
+		}
+	}
+
+	return builder.String()
+}
+
+type liveClassHandle struct {
+	handle IClassBase
+	stack  string
+}
+
+func captureStack(enabled bool) string {
+	if !enabled {
+		return ""
+	}
+	return string(debug.Stack())
+}
+
+// trackClassHandle registers the handle as live until it is deleted. Handles
+// that share their record (like implemented classes) are tracked once.
+func (e *engine) trackClassHandle(handle IClassBase) {
+	if e == nil {
+		return
+	}
+
+	record := handle.getRegisteredPtrTypeRecord()
+	existing, ok := e.liveClassHandles[record]
+	if ok {
+		existing.handle = handle
+		return
+	}
+
+	e.liveClassHandles[record] = &liveClassHandle{
+		handle: handle,
+		stack:  captureStack(e.captureHandleStacks),
+	}
+}
+
+func (e *engine) untrackClassHandle(handle IClassBase) {
+	if e == nil {
+		return
+	}
+
+	delete(e.liveClassHandles, handle.getRegisteredPtrTypeRecord())
+}
+
+func (e *engine) LeakReport() *LeakReport {
+	report := &LeakReport{
+		ClassHandles: []LiveClassHandle{},
+		EmvalHandles: []LiveEmvalHandle{},
+	}
+
+	for record, liveHandle := range e.liveClassHandles {
+		smartPointerType := ""
+		if record.smartPtrType != nil {
+			smartPointerType = record.smartPtrType.name
+		}
+
+		report.ClassHandles = append(report.ClassHandles, LiveClassHandle{
+			ClassName:        record.ptrType.registeredClass.name,
+			Pointer:          record.ptr,
+			Handle:           liveHandle.handle,
+			RefCount:         record.count.value,
+			SmartPointerType: smartPointerType,
+			Stack:            liveHandle.stack,
+		})
+	}
+
+	sort.Slice(report.ClassHandles, func(i, j int) bool {
+		if report.ClassHandles[i].ClassName == report.ClassHandles[j].ClassName {
+			return report.ClassHandles[i].Pointer < report.ClassHandles[j].Pointer
+		}
+		return report.ClassHandles[i].ClassName < report.ClassHandles[j].ClassName
+	})
+
+	allocator := e.emvalEngine.allocator
+	for id := allocator.reserved; id < len(allocator.allocated); id++ {
+		if allocator.allocated[id] == nil {
+			continue
+		}
+
+		report.EmvalHandles = append(report.EmvalHandles, LiveEmvalHandle{
+			ID:       int32(id),
+			Value:    allocator.allocated[id].value,
+			RefCount: allocator.allocated[id].refCount,
+			Stack:    allocator.allocated[id].stack,
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,106 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// allocation is a block of memory that is allocated with Alloc.
+type allocation struct {
+	engine *engine
+	ptr    uint32
+	size   uint32
+	freed  bool
+}
+
+func (a *allocation) free(ctx context.Context) error {
+	if a.freed {
+		return nil
+	}
+	a.freed = true
+	delete(a.engine.allocations, a.ptr)
+
+	_, err := a.engine.mod.ExportedFunction("free").Call(ctx, api.EncodeU32(a.ptr))
+	if err != nil {
+		return fmt.Errorf("could not free %d bytes at pointer %d: %w", a.size, a.ptr, err)
+	}
+	return nil
+}
+
+func (e *engine) Alloc(ctx context.Context, size uint32) (uint32, error) {
+	if e.mod == nil {
+		return 0, fmt.Errorf("could not allocate %d bytes: the module has not been instantiated", size)
+	}
+
+	res, err := e.mod.ExportedFunction("malloc").Call(e.Attach(ctx), api.EncodeU32(size))
+	if err != nil {
+		return 0, fmt.Errorf("could not allocate %d bytes: %w", size, err)
+	}
+
+	ptr := api.DecodeU32(res[0])
+	if ptr == 0 {
+		return 0, fmt.Errorf("could not allocate %d bytes: out of memory", size)
+	}
+
+	a := &allocation{
+		engine: e,
+		ptr:    ptr,
+		size:   size,
+	}
+	e.allocations[ptr] = a
+
+	if scope := GetScopeFromContext(ctx); scope != nil {
+		scope.recordAllocation(a)
+	}
+
+	return ptr, nil
+}
+
+func (e *engine) Free(ctx context.Context, ptr uint32) error {
+	if ptr == 0 {
+		return nil
+	}
+
+	a, ok := e.allocations[ptr]
+	if !ok {
+		return fmt.Errorf("could not free pointer %d, it has not been allocated with Alloc or it has already been freed", ptr)
+	}
+
+	return a.free(e.Attach(ctx))
+}
+
+func (e *engine) WriteBytes(ptr uint32, data []byte) error {
+	if e.mod == nil {
+		return fmt.Errorf("could not write %d bytes at pointer %d: the module has not been instantiated", len(data), ptr)
+	}
+
+	if !e.mod.Memory().Write(ptr, data) {
+		return fmt.Errorf("could not write %d bytes at pointer %d: out of range", len(data), ptr)
+	}
+	return nil
+}
+
+func (e *engine) ReadBytes(ptr uint32, size uint32) ([]byte, error) {
+	view, err := e.ViewBytes(ptr, size)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, size)
+	copy(data, view)
+	return data, nil
+}
+
+func (e *engine) ViewBytes(ptr uint32, size uint32) ([]byte, error) {
+	if e.mod == nil {
+		return nil, fmt.Errorf("could not read %d bytes at pointer %d: the module has not been instantiated", size, ptr)
+	}
+
+	view, ok := e.mod.Memory().Read(ptr, size)
+	if !ok {
+		return nil, fmt.Errorf("could not read %d bytes at pointer %d: out of range", size, ptr)
+	}
+	return view, nil
+}
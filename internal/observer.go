@@ -0,0 +1,42 @@
+package embind
+
+import (
+	"context"
+	"time"
+)
+
+// IObserver can be configured on the engine to get notified about every call
+// that crosses the embind boundary, for example to collect metrics or traces.
+// Observers are called synchronously, so they should return quickly.
+type IObserver interface {
+	// OnCall is called after Go called into C++. This covers functions,
+	// constructors, methods and static methods. The className is empty for
+	// functions that are not part of a class.
+	OnCall(ctx context.Context, symbol, className string, arguments []any, result any, err error, duration time.Duration)
+
+	// OnEmvalCall is called after C++ called into Go using emval. The method
+	// is empty when the value itself has been called as a function and is
+	// "new" when C++ constructed a new instance of the value.
+	OnEmvalCall(ctx context.Context, value any, method string, arguments []any, result any, err error, duration time.Duration)
+}
+
+func (e *engine) observeCall(ctx context.Context, symbol, className string, arguments []any, call func() (any, error)) (any, error) {
+	return e.trackCall(ctx, func() (any, error) {
+		if e.observer == nil {
+			return call()
+		}
+
+		start := time.Now()
+		res, err := call()
+		e.observer.OnCall(ctx, symbol, className, arguments, res, err, time.Since(start))
+		return res, err
+	})
+}
+
+func (e *engine) observeEmvalCall(ctx context.Context, value any, method string, arguments []any, result any, err error, start time.Time) {
+	if e.observer == nil {
+		return
+	}
+
+	e.observer.OnEmvalCall(ctx, value, method, arguments, result, err, time.Since(start))
+}
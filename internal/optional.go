@@ -0,0 +1,121 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jerbob92/wazero-emscripten-embind/types"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// optionalType is a std::optional<T>. Emscripten passes optionals as an
+// emval, which is undefined when the optional is empty and contains the
+// value otherwise. In Go, the value is a pointer to the value, or nil when
+// the optional is empty. Types that can already be nil, like classes, are
+// used as-is.
+type optionalType struct {
+	baseType
+	valueType registeredType
+}
+
+// valueIsNillable returns whether the value type can already be nil in Go.
+func (ot *optionalType) valueIsNillable() bool {
+	if _, ok := ot.valueType.(*userType); ok {
+		return true
+	}
+	goType := ot.valueType.GoType()
+	return goType == "any" || strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "[]") || strings.HasPrefix(goType, "map[")
+}
+
+func (ot *optionalType) FromWireType(ctx context.Context, mod api.Module, value uint64) (any, error) {
+	e := MustGetEngineFromContext(ctx, mod).(*engine)
+	rv, err := e.emvalEngine.toValue(api.DecodeI32(value))
+	if err != nil {
+		return nil, err
+	}
+
+	err = e.emvalEngine.allocator.decref(api.DecodeI32(value))
+	if err != nil {
+		return nil, err
+	}
+
+	if rv == types.Undefined || rv == nil {
+		return nil, nil
+	}
+
+	if ot.valueIsNillable() {
+		return rv, nil
+	}
+
+	pointer := reflect.New(reflect.TypeOf(rv))
+	pointer.Elem().Set(reflect.ValueOf(rv))
+	return pointer.Interface(), nil
+}
+
+func (ot *optionalType) ToWireType(ctx context.Context, mod api.Module, destructors *[]*destructorFunc, o any) (uint64, error) {
+	e := MustGetEngineFromContext(ctx, mod).(*engine)
+
+	if o == nil || o == types.Undefined {
+		return api.EncodeI32(e.emvalEngine.toHandle(types.Undefined)), nil
+	}
+
+	value := reflect.ValueOf(o)
+	if value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return api.EncodeI32(e.emvalEngine.toHandle(types.Undefined)), nil
+		}
+
+		// Pass the value behind the pointer, unless the pointer is the
+		// value itself, like a class instance.
+		if !ot.valueIsNillable() {
+			o = value.Elem().Interface()
+		}
+	}
+
+	return api.EncodeI32(e.emvalEngine.toHandle(o)), nil
+}
+
+func (ot *optionalType) ReadValueFromPointer(ctx context.Context, mod api.Module, pointer uint32) (any, error) {
+	value, ok := mod.Memory().ReadUint32Le(pointer)
+	if !ok {
+		return nil, fmt.Errorf("could not read optional value at pointer %d", pointer)
+	}
+	return ot.FromWireType(ctx, mod, api.EncodeU32(value))
+}
+
+func (ot *optionalType) DestructorFunctionUndefined() bool {
+	return false
+}
+
+func (ot *optionalType) GoType() string {
+	if ot.valueIsNillable() {
+		return ot.valueType.GoType()
+	}
+	return "*" + ot.valueType.GoType()
+}
+
+var RegisterOptional = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+
+	rawOptionalType := api.DecodeI32(stack[0])
+	rawType := api.DecodeI32(stack[1])
+
+	err := engine.whenDependentTypesAreResolved([]int32{rawOptionalType}, []int32{rawType}, func(valueTypes []registeredType) ([]registeredType, error) {
+		return []registeredType{
+			&optionalType{
+				baseType: baseType{
+					rawType:        rawOptionalType,
+					name:           "std::optional<" + valueTypes[0].Name() + ">",
+					argPackAdvance: GenericWireTypeSize,
+				},
+				valueType: valueTypes[0],
+			},
+		}, nil
+	})
+	if err != nil {
+		panic(fmt.Errorf("could not call whenDependentTypesAreResolved: %w", err))
+	}
+})
@@ -0,0 +1,152 @@
+package embind
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPromisePending is returned by Promise.Result when the promise has not
+// been resolved or rejected yet.
+var ErrPromisePending = errors.New("promise is still pending")
+
+// ErrCppException is the value of the Emval handles that Emscripten creates
+// for C++ exceptions, like the rejection of a coroutine that threw. The
+// exception itself can not be inspected from Go.
+var ErrCppException = errors.New("C++ exception")
+
+// Promise is the Go counterpart of a JS promise. C++ coroutines that return
+// an emscripten::val return a Promise, and C++ coroutines can co_await
+// promises that are created in Go.
+type Promise struct {
+	settled   bool
+	value     any
+	err       error
+	callbacks []func(ctx context.Context, value any, err error) error
+}
+
+// NewPromise creates a new pending promise.
+func NewPromise() *Promise {
+	return &Promise{}
+}
+
+// Resolve resolves the promise with the given value and runs the callbacks,
+// which may resume C++ coroutines, so the context has to contain the engine.
+// Settling a promise that is already settled is a no-op.
+func (p *Promise) Resolve(ctx context.Context, value any) error {
+	return p.settle(ctx, value, nil)
+}
+
+// Reject rejects the promise with the given error, like Resolve.
+func (p *Promise) Reject(ctx context.Context, err error) error {
+	if err == nil {
+		err = errors.New("promise rejected")
+	}
+	return p.settle(ctx, nil, err)
+}
+
+func (p *Promise) settle(ctx context.Context, value any, err error) error {
+	if p.settled {
+		return nil
+	}
+
+	p.settled = true
+	p.value = value
+	p.err = err
+
+	callbacks := p.callbacks
+	p.callbacks = nil
+	for i := range callbacks {
+		callbackErr := callbacks[i](ctx, value, err)
+		if callbackErr != nil {
+			return callbackErr
+		}
+	}
+
+	return nil
+}
+
+// Settled returns whether the promise has been resolved or rejected.
+func (p *Promise) Settled() bool {
+	return p.settled
+}
+
+// Result returns the value or the error of the promise, or
+// ErrPromisePending when it has not been settled yet.
+func (p *Promise) Result() (any, error) {
+	if !p.settled {
+		return nil, ErrPromisePending
+	}
+	return p.value, p.err
+}
+
+// Then registers a callback that is called when the promise is settled, or
+// right away when it is already settled.
+func (p *Promise) Then(ctx context.Context, callback func(ctx context.Context, value any, err error) error) error {
+	if p.settled {
+		return callback(ctx, p.value, p.err)
+	}
+	p.callbacks = append(p.callbacks, callback)
+	return nil
+}
+
+// queueCoroResume queues the resume of a C++ coroutine. The queue runs when
+// the top-level call returns, or through the delay function when one is set.
+// When no call is running, like when Go settles a promise, it runs right
+// away.
+func (e *engine) queueCoroResume(ctx context.Context, resume func(ctx context.Context) error) error {
+	e.coroResumes = append(e.coroResumes, resume)
+
+	// The run that is scheduled with the delay function also runs this
+	// resume.
+	if e.coroResumesScheduled {
+		return nil
+	}
+
+	if e.delayFunction != nil {
+		return e.scheduleCoroResumes()
+	}
+
+	if e.callDepth > 0 {
+		return nil
+	}
+
+	return e.runCoroResumes(ctx)
+}
+
+// scheduleCoroResumes schedules a run of the queued coroutine resumes with
+// the delay function.
+func (e *engine) scheduleCoroResumes() error {
+	e.coroResumesScheduled = true
+	err := e.delayFunction(func(ctx context.Context) error {
+		e.coroResumesScheduled = false
+		return e.runCoroResumes(ctx)
+	})
+	if err != nil {
+		e.coroResumesScheduled = false
+		return err
+	}
+	return nil
+}
+
+// runCoroResumes runs the queued coroutine resumes in the order in which
+// they were queued, including the resumes that are queued while running.
+func (e *engine) runCoroResumes(ctx context.Context) error {
+	if e.mod == nil || e.mod.IsClosed() {
+		return nil
+	}
+
+	ctx = e.Attach(ctx)
+	for len(e.coroResumes) > 0 {
+		resume := e.coroResumes[0]
+		e.coroResumes = e.coroResumes[1:]
+
+		_, err := e.trackCallDepth(func() (any, error) {
+			return nil, resume(ctx)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package embind
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
@@ -23,9 +24,20 @@ type registeredPointerType struct {
 	rawShare       api.Function
 	rawDestructor  api.Function
 
-	specialShare bool // Whether the rawShare is special for this class (no return param)
+	specialShare   bool // Whether the rawShare returns the smart pointer by value (no return param)
+	isStdSharedPtr bool // Whether the smart pointer is a std::shared_ptr, see SharedPointer
+
+	sharedPtrLayoutChecked bool  // Whether the layout of the std::shared_ptr has been checked, see checkSharedPtrLayout
+	sharedPtrLayoutErr     error // The result of the layout check
 }
 
+// The sharing policies of smart_ptr_trait.
+const (
+	sharingPolicyNone      = 0
+	sharingPolicyIntrusive = 1
+	sharingPolicyByEmval   = 2
+)
+
 type registeredPointerTypeRecordCount struct {
 	value int32
 }
@@ -38,6 +50,8 @@ type registeredPointerTypeRecord struct {
 	count                   *registeredPointerTypeRecordCount // The reason this is a reference to another struct is to easily pass a reference around when cloning classes.
 	preservePointerOnDelete bool
 	deleteScheduled         bool
+	castFrom                *registeredPointerTypeRecord // The record of the instance before it was cast to another class, used to run the right destructor.
+	borrowed                bool                         // Whether the instance is owned by C++, the destructor is not run when the last handle is deleted, see WrapPointer.
 }
 
 func (rptr *registeredPointerTypeRecord) shallowCopyInternalPointer() *registeredPointerTypeRecord {
@@ -49,6 +63,8 @@ func (rptr *registeredPointerTypeRecord) shallowCopyInternalPointer() *registere
 		ptrType:                 rptr.ptrType,
 		smartPtr:                rptr.smartPtr,
 		smartPtrType:            rptr.smartPtrType,
+		castFrom:                rptr.castFrom,
+		borrowed:                rptr.borrowed,
 	}
 }
 
@@ -62,6 +78,14 @@ func (rptr *registeredPointerTypeRecord) releaseClassHandle(ctx context.Context)
 }
 
 func (rptr *registeredPointerTypeRecord) runDestructor(ctx context.Context) error {
+	if rptr.castFrom != nil {
+		return rptr.castFrom.runDestructor(ctx)
+	}
+
+	if rptr.borrowed {
+		return nil
+	}
+
 	if rptr.smartPtr != 0 {
 		_, err := rptr.smartPtrType.rawDestructor.Call(ctx, api.EncodeU32(rptr.smartPtr))
 		if err != nil {
@@ -214,7 +238,7 @@ func (rpt *registeredPointerType) GoType() string {
 }
 
 func (rpt *registeredPointerType) constNoSmartPtrRawPointerToWireType(ctx context.Context, mod api.Module, destructors *[]*destructorFunc, o any) (uint64, error) {
-	if o == nil {
+	if o == nil || isNilPointer(o) {
 		if rpt.isReference {
 			return 0, fmt.Errorf("nil is not a valid %s", rpt.name)
 		}
@@ -255,7 +279,7 @@ func (rpt *registeredPointerType) constNoSmartPtrRawPointerToWireType(ctx contex
 }
 
 func (rpt *registeredPointerType) nonConstNoSmartPtrRawPointerToWireType(ctx context.Context, mod api.Module, destructors *[]*destructorFunc, o any) (uint64, error) {
-	if o == nil {
+	if o == nil || isNilPointer(o) {
 		if rpt.isReference {
 			return 0, fmt.Errorf("nil is not a valid %s", rpt.name)
 		}
@@ -300,7 +324,7 @@ func (rpt *registeredPointerType) nonConstNoSmartPtrRawPointerToWireType(ctx con
 
 func (rpt *registeredPointerType) genericPointerToWireType(ctx context.Context, mod api.Module, destructors *[]*destructorFunc, o any) (uint64, error) {
 	var ptr uint32
-	if o == nil {
+	if o == nil || isNilPointer(o) {
 		if rpt.isReference {
 			return 0, fmt.Errorf("nil is not a valid %s", rpt.name)
 		}
@@ -365,16 +389,14 @@ func (rpt *registeredPointerType) genericPointerToWireType(ctx context.Context,
 	}
 
 	if rpt.isSmartPointer {
-		// TODO: this is not strictly true (comment from Emscripten)
-		// 		 We could support BY_EMVAL conversions from raw pointers to smart pointers
-		// 		 because the smart pointer can hold a reference to the handle
-		if registeredPtrTypeRecord.smartPtr == 0 {
-			return 0, fmt.Errorf("passing raw pointer to smart pointer is illegal")
-		}
-
 		switch rpt.sharingPolicy {
-		case 0:
-			// no upcasting
+		case sharingPolicyNone:
+			// A raw pointer can't be turned into a smart pointer that does
+			// not share ownership, and there is no upcasting.
+			if registeredPtrTypeRecord.smartPtr == 0 {
+				return 0, fmt.Errorf("passing raw pointer to smart pointer %s is illegal, its sharing policy is NONE", rpt.name)
+			}
+
 			if registeredPtrTypeRecord.smartPtrType == rpt {
 				ptr = registeredPtrTypeRecord.smartPtr
 			} else {
@@ -384,50 +406,136 @@ func (rpt *registeredPointerType) genericPointerToWireType(ctx context.Context,
 				}
 				return 0, fmt.Errorf("cannot convert argument of type %s to parameter type %s", typeName, rpt.name)
 			}
-			break
-
-		case 1: // INTRUSIVE
-			ptr = registeredPtrTypeRecord.smartPtr
-			break
-		case 2: // BY_EMVAL
-			if registeredPtrTypeRecord.smartPtrType == rpt {
+		case sharingPolicyIntrusive:
+			if registeredPtrTypeRecord.smartPtr != 0 {
 				ptr = registeredPtrTypeRecord.smartPtr
 			} else {
-				e := MustGetEngineFromContext(ctx, nil)
-				clonedHandle, err := handle.getClassType().clone(ctx, handle)
+				// The reference count lives in the object, so a new smart
+				// pointer can be made from the raw pointer.
+				ptr, err = rpt.shareIntrusive(ctx, ptr, destructors)
 				if err != nil {
 					return 0, err
 				}
-
-				deleteCallbackHandle := e.(*engine).emvalEngine.toHandle(func() {
-					// @todo: do something with this error?
-					_ = clonedHandle.getClassType().delete(ctx, clonedHandle)
-				})
-
-				// @todo: what to do with rpt.specialShare?
-				res, err := rpt.rawShare.Call(ctx, api.EncodeU32(ptr), api.EncodeI32(deleteCallbackHandle))
+			}
+		case sharingPolicyByEmval:
+			if registeredPtrTypeRecord.smartPtrType == rpt {
+				ptr = registeredPtrTypeRecord.smartPtr
+			} else {
+				// Raw pointers and other smart pointers are shared by
+				// keeping a clone of the handle alive until C++ releases
+				// the new smart pointer.
+				ptr, err = rpt.shareByEmval(ctx, handle, ptr, destructors)
 				if err != nil {
 					return 0, err
 				}
-				ptr = api.DecodeU32(res[0])
-				if destructors != nil {
-					destructorsRef := *destructors
-					destructorsRef = append(destructorsRef, &destructorFunc{
-						apiFunction: rpt.rawDestructor,
-						args:        []uint64{api.EncodeU32(ptr)},
-					})
-					*destructors = destructorsRef
-				}
 			}
-			break
 		default:
-			return 0, fmt.Errorf("unsupporting sharing policy")
+			return 0, fmt.Errorf("unsupported sharing policy %d of %s", rpt.sharingPolicy, rpt.name)
 		}
 	}
 
 	return api.EncodeU32(ptr), nil
 }
 
+// ErrUnknownSmartPointerSize is returned when a smart pointer has to be made
+// through a share function that returns the smart pointer by value. The
+// result has to be constructed in memory of the right size, but embind
+// doesn't register the size of smart pointers.
+var ErrUnknownSmartPointerSize = errors.New("the share function returns the smart pointer by value, but the size of the smart pointer is unknown")
+
+// share calls the share function of the smart_ptr_trait and returns the
+// pointer to the new smart pointer. Traits that return the smart pointer by
+// value are not supported, see ErrUnknownSmartPointerSize.
+func (rpt *registeredPointerType) share(ctx context.Context, args ...uint64) (uint32, error) {
+	if rpt.specialShare {
+		return 0, fmt.Errorf("could not share %s: %w", rpt.name, ErrUnknownSmartPointerSize)
+	}
+
+	res, err := rpt.rawShare.Call(ctx, args...)
+	if err != nil {
+		return 0, err
+	}
+	return api.DecodeU32(res[0]), nil
+}
+
+// addSmartPointerDestructor makes sure that a smart pointer that was created
+// for a call is destructed after the call.
+func (rpt *registeredPointerType) addSmartPointerDestructor(destructors *[]*destructorFunc, smartPtr uint32) {
+	if destructors == nil {
+		return
+	}
+
+	*destructors = append(*destructors, &destructorFunc{
+		apiFunction: rpt.rawDestructor,
+		args:        []uint64{api.EncodeU32(smartPtr)},
+	})
+}
+
+// shareIntrusive creates an intrusive smart pointer from a raw pointer. The
+// share function of intrusive traits takes a smart pointer of the same type
+// and the raw pointer, a null smart pointer is given for the former.
+func (rpt *registeredPointerType) shareIntrusive(ctx context.Context, ptr uint32, destructors *[]*destructorFunc) (smartPtr uint32, err error) {
+	res, err := rpt.rawConstructor.Call(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	// The null smart pointer is only needed for the call to share, it's
+	// destructed on success and on failure.
+	nullPtr := api.DecodeU32(res[0])
+	defer func() {
+		_, destructorErr := rpt.rawDestructor.Call(ctx, api.EncodeU32(nullPtr))
+		if destructorErr != nil && err == nil {
+			err = destructorErr
+		}
+	}()
+
+	smartPtr, err = rpt.share(ctx, api.EncodeU32(nullPtr), api.EncodeU32(ptr))
+	if err != nil {
+		return 0, err
+	}
+
+	rpt.addSmartPointerDestructor(destructors, smartPtr)
+	return smartPtr, nil
+}
+
+// shareByEmval creates a smart pointer that holds an emval of a delete
+// callback, which deletes a clone of the handle when C++ releases the smart
+// pointer. Errors of the callback are returned to the C++ code that released
+// the smart pointer, and end up as the error of the call that did that.
+func (rpt *registeredPointerType) shareByEmval(ctx context.Context, handle IClassBase, ptr uint32, destructors *[]*destructorFunc) (uint32, error) {
+	e := MustGetEngineFromContext(ctx, nil).(*engine)
+	clonedHandle, err := handle.getClassType().clone(ctx, handle)
+	if err != nil {
+		return 0, err
+	}
+
+	deleteCallbackHandle := e.emvalEngine.toHandle(func(ctx context.Context) error {
+		err := clonedHandle.getClassType().delete(ctx, clonedHandle)
+		if err != nil {
+			return fmt.Errorf("could not delete the %s that was shared with %s: %w", clonedHandle.getClassType().name, rpt.name, err)
+		}
+		return nil
+	})
+
+	smartPtr, err := rpt.share(ctx, api.EncodeU32(ptr), api.EncodeI32(deleteCallbackHandle))
+	if err != nil {
+		// The smart pointer doesn't own the callback, so the clone has to be
+		// deleted here.
+		cleanupErr := e.emvalEngine.allocator.decref(deleteCallbackHandle)
+		if cleanupErr == nil {
+			cleanupErr = clonedHandle.getClassType().delete(ctx, clonedHandle)
+		}
+		if cleanupErr != nil {
+			return 0, fmt.Errorf("%w (could not delete the clone of the handle: %v)", err, cleanupErr)
+		}
+		return 0, err
+	}
+
+	rpt.addSmartPointerDestructor(destructors, smartPtr)
+	return smartPtr, nil
+}
+
 func (rpt *registeredPointerType) ReadValueFromPointer(ctx context.Context, mod api.Module, pointer uint32) (any, error) {
 	value, ok := mod.Memory().ReadUint32Le(pointer)
 	if !ok {
@@ -614,3 +722,29 @@ func (rpt *registeredPointerType) upcastPointer(ctx context.Context, ptr uint32,
 
 	return ptr, nil
 }
+
+// isNilPointer returns whether o is a nil pointer of any type. For class
+// arguments this is a nil *ClassFoo that is passed as a typed argument of
+// generated code.
+func isNilPointer(o any) bool {
+	value := reflect.ValueOf(o)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// ErrNullPointer is returned when C++ returns a null pointer to a class and
+// the engine is configured with WithNullPointerErrors(true).
+var ErrNullPointer = errors.New("unexpected null pointer")
+
+// checkNullPointer returns ErrNullPointer when a null pointer to a class is
+// returned by C++ while the engine doesn't allow it.
+func (e *engine) checkNullPointer(humanName string, valueType registeredType, value any) error {
+	if !e.nullPointerErrors || value != nil {
+		return nil
+	}
+
+	if _, ok := valueType.(*registeredPointerType); !ok {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s returned a null %s", ErrNullPointer, humanName, valueType.Name())
+}
@@ -0,0 +1,123 @@
+package embind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+type scopeKey struct{}
+
+// Scope records the class handles that are returned by calls made with a
+// context that contains the scope, and the memory that is allocated with
+// Alloc, so that they can be deleted at once when the scope is closed.
+type Scope struct {
+	handles     []IClassBase
+	kept        map[IClassBase]bool
+	allocations []*allocation
+	closed      bool
+}
+
+// NewScope creates a new scope and returns a context that contains it. Every
+// class handle that is returned by a call made with the returned context is
+// recorded in the scope. When a context already contains a scope, the new
+// scope replaces it for the returned context.
+func NewScope(ctx context.Context) (context.Context, *Scope) {
+	scope := &Scope{
+		handles: []IClassBase{},
+		kept:    map[IClassBase]bool{},
+	}
+	return context.WithValue(ctx, scopeKey{}, scope), scope
+}
+
+// GetScopeFromContext returns the scope in the context, or nil when the
+// context does not contain a scope.
+func GetScopeFromContext(ctx context.Context) *Scope {
+	scope, ok := ctx.Value(scopeKey{}).(*Scope)
+	if !ok {
+		return nil
+	}
+	return scope
+}
+
+func (s *Scope) record(handle IClassBase) {
+	if s.closed {
+		return
+	}
+	s.handles = append(s.handles, handle)
+}
+
+func (s *Scope) recordAllocation(a *allocation) {
+	if s.closed {
+		return
+	}
+	s.allocations = append(s.allocations, a)
+}
+
+// Keep makes sure that the given class handle is not deleted when the scope is
+// closed. You have to delete the handle yourself when you don't need it
+// anymore.
+func (s *Scope) Keep(obj IClassBase) {
+	s.kept[obj] = true
+}
+
+// KeepMemory makes sure that the memory at the given pointer, that has been
+// allocated with Alloc, is not freed when the scope is closed. You have to
+// free it yourself, or hand it over to C++.
+func (s *Scope) KeepMemory(ptr uint32) {
+	for i := range s.allocations {
+		if s.allocations[i].ptr == ptr && !s.allocations[i].freed {
+			s.allocations = append(s.allocations[:i], s.allocations[i+1:]...)
+			return
+		}
+	}
+}
+
+// Close deletes all the recorded class handles that have not been deleted
+// yet and that are not kept, and then frees the memory that has not been
+// freed yet and that is not kept. The handles and the memory are released in
+// the reverse order of their creation. Closing a scope more than once is a
+// no-op.
+func (s *Scope) Close(ctx context.Context) error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var errs []error
+	for i := len(s.handles) - 1; i >= 0; i-- {
+		handle := s.handles[i]
+		if s.kept[handle] {
+			continue
+		}
+
+		record := handle.getRegisteredPtrTypeRecord()
+		if record.ptr == 0 || record.count.value == 0 {
+			continue
+		}
+
+		// Handles that are scheduled for deletion are deleted by the flush.
+		if record.deleteScheduled && !record.preservePointerOnDelete {
+			continue
+		}
+
+		err := handle.DeleteInstance(handle.getEngine().Attach(ctx), handle)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not delete %s: %w", handle.getClassType().name, err))
+		}
+	}
+
+	s.handles = nil
+
+	for i := len(s.allocations) - 1; i >= 0; i-- {
+		a := s.allocations[i]
+		err := a.free(a.engine.Attach(ctx))
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	s.allocations = nil
+
+	return errors.Join(errs...)
+}
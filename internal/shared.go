@@ -0,0 +1,476 @@
+package embind
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental/table"
+)
+
+var stdSharedPtrTypeName = regexp.MustCompile(`^std::(__\w+::)?shared_ptr<`)
+
+// ErrNotSharedPointer is returned when a SharedPointer is made of a class
+// instance that does not hold a std::shared_ptr.
+var ErrNotSharedPointer = errors.New("the instance does not hold a std::shared_ptr")
+
+// ErrUnsupportedSharedPointerLayout is returned when a SharedPointer is made
+// of a std::shared_ptr that does not have the layout of libc++ that
+// SharedPointer relies on, or that is in memory that may be shared with
+// other threads.
+var ErrUnsupportedSharedPointerLayout = errors.New("the std::shared_ptr does not have the supported libc++ layout")
+
+// The layout of std::shared_ptr and its control block in libc++, which is
+// the standard library of Emscripten. A shared_ptr is the element pointer
+// followed by the pointer to the control block. The control block is a
+// __shared_weak_count: the vtable, the number of owners minus one and the
+// number of weak owners. The control block deletes itself and the object
+// through its virtual functions. The reference counts are written without
+// atomics, like libc++ does in builds without pthreads, so modules with a
+// memory that may be shared with other threads are refused. The layout is
+// checked for every smart pointer type before it's used, see
+// checkSharedPtrLayout.
+const (
+	sharedPtrSize               = 8
+	sharedPtrControlBlockOffset = 4
+
+	controlBlockSharedOwnersOffset = 4
+	controlBlockWeakOwnersOffset   = 8
+
+	controlBlockOnZeroSharedWeakVtableOffset = 16
+)
+
+// sharedPointerReference is a reference to the control block of a
+// std::shared_ptr, it's shared by SharedPointer and WeakPointer.
+type sharedPointerReference struct {
+	engine       *engine
+	class        *classType
+	ptrType      *registeredPointerType
+	ptr          uint32
+	smartPtrType *registeredPointerType
+	element      uint32
+	controlBlock uint32
+}
+
+func (r *sharedPointerReference) read(offset uint32) (int32, error) {
+	value, ok := r.engine.mod.Memory().ReadUint32Le(r.controlBlock + offset)
+	if !ok {
+		return 0, fmt.Errorf("could not read the control block of %s at pointer %d", r.smartPtrType.name, r.controlBlock)
+	}
+	return int32(value), nil
+}
+
+func (r *sharedPointerReference) write(offset uint32, value int32) error {
+	if !r.engine.mod.Memory().WriteUint32Le(r.controlBlock+offset, uint32(value)) {
+		return fmt.Errorf("could not write the control block of %s at pointer %d", r.smartPtrType.name, r.controlBlock)
+	}
+	return nil
+}
+
+func (r *sharedPointerReference) add(offset uint32) error {
+	value, err := r.read(offset)
+	if err != nil {
+		return err
+	}
+	return r.write(offset, value+1)
+}
+
+// useCount returns the number of std::shared_ptr that own the object.
+func (r *sharedPointerReference) useCount() (int32, error) {
+	if r.controlBlock == 0 {
+		return 0, nil
+	}
+
+	sharedOwners, err := r.read(controlBlockSharedOwnersOffset)
+	if err != nil {
+		return 0, err
+	}
+	return sharedOwners + 1, nil
+}
+
+// newSharedPointer creates a new std::shared_ptr that shares the ownership,
+// like the copy constructor does, and wraps it in a new class handle. The
+// copy is written in memory from malloc, the same memory that operator new
+// uses, so that the destructor of the smart pointer can delete it.
+func (r *sharedPointerReference) newSharedPointer(ctx context.Context) (*SharedPointer, error) {
+	res, err := r.engine.mod.ExportedFunction("malloc").Call(ctx, api.EncodeU32(sharedPtrSize))
+	if err != nil {
+		return nil, fmt.Errorf("could not allocate memory for %s: %w", r.smartPtrType.name, err)
+	}
+
+	smartPtr := api.DecodeU32(res[0])
+	if smartPtr == 0 {
+		return nil, fmt.Errorf("could not allocate memory for %s: out of memory", r.smartPtrType.name)
+	}
+
+	memory := r.engine.mod.Memory()
+	if !memory.WriteUint32Le(smartPtr, r.element) || !memory.WriteUint32Le(smartPtr+sharedPtrControlBlockOffset, r.controlBlock) {
+		return nil, fmt.Errorf("could not write %s at pointer %d", r.smartPtrType.name, smartPtr)
+	}
+
+	err = r.add(controlBlockSharedOwnersOffset)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := r.smartPtrType.makeClassHandle(ctx, r.class, &registeredPointerTypeRecord{
+		ptrType:      r.ptrType,
+		ptr:          r.ptr,
+		smartPtrType: r.smartPtrType,
+		smartPtr:     smartPtr,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &SharedPointer{
+		handle:    handle,
+		reference: r,
+	}, nil
+}
+
+// lookupOnZeroSharedWeak returns the __on_zero_shared_weak() function of a
+// control block, which deletes the control block.
+func (r *sharedPointerReference) lookupOnZeroSharedWeak(controlBlock uint32) (api.Function, error) {
+	memory := r.engine.mod.Memory()
+	vtable, ok := memory.ReadUint32Le(controlBlock)
+	if !ok {
+		return nil, fmt.Errorf("could not read the control block of %s at pointer %d", r.smartPtrType.name, controlBlock)
+	}
+
+	onZeroSharedWeak, ok := memory.ReadUint32Le(vtable + controlBlockOnZeroSharedWeakVtableOffset)
+	if !ok {
+		return nil, fmt.Errorf("could not read the vtable of the control block of %s", r.smartPtrType.name)
+	}
+
+	var lookupErr error
+	f := func() api.Function {
+		defer func() {
+			if recoverErr := recover(); recoverErr != nil {
+				lookupErr = fmt.Errorf("could not find the __on_zero_shared_weak function of the control block of %s: %v", r.smartPtrType.name, recoverErr)
+			}
+		}()
+		return table.LookupFunction(r.engine.mod, 0, onZeroSharedWeak, []api.ValueType{api.ValueTypeI32}, []api.ValueType{})
+	}()
+	if lookupErr != nil {
+		return nil, lookupErr
+	}
+
+	return f, nil
+}
+
+// usesSharedMemory returns whether the memory of the module may be shared
+// with other threads. wazero doesn't tell whether a memory is shared, but
+// Emscripten imports the memory and exports the initialization of threads in
+// builds with -pthread or -sWASM_WORKERS, so an imported memory is treated
+// as shared as well.
+func (e *engine) usesSharedMemory() bool {
+	if _, _, isImport := e.mod.Memory().Definition().Import(); isImport {
+		return true
+	}
+
+	for _, name := range []string{"_emscripten_thread_init", "_emscripten_wasm_worker_initialize"} {
+		if e.mod.ExportedFunction(name) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSharedPtrLayout makes sure that the smart pointer type has the layout
+// that SharedPointer reads and writes, so that an unexpected standard library
+// results in an error instead of a corrupted heap. The element pointer of
+// the smart pointer is compared with the one that C++ returns for it, and a
+// second std::shared_ptr to the element is made through the share function
+// of the trait: its control block must have the expected counts and vtable,
+// and destructing it must release the last owner. The result is remembered
+// for the smart pointer type.
+func (r *sharedPointerReference) checkSharedPtrLayout(ctx context.Context, smartPtr uint32) error {
+	rpt := r.smartPtrType
+	if rpt.sharedPtrLayoutChecked {
+		return rpt.sharedPtrLayoutErr
+	}
+
+	err := r.validateSharedPtrLayout(ctx, smartPtr)
+	if err != nil {
+		// Errors of the module itself are not a layout problem, they are
+		// returned without remembering them.
+		if !errors.Is(err, ErrUnsupportedSharedPointerLayout) {
+			return err
+		}
+	}
+
+	rpt.sharedPtrLayoutChecked = true
+	rpt.sharedPtrLayoutErr = err
+	return err
+}
+
+func (r *sharedPointerReference) validateSharedPtrLayout(ctx context.Context, smartPtr uint32) error {
+	rpt := r.smartPtrType
+	unsupported := func(reason string) error {
+		return fmt.Errorf("%w: %s %s", ErrUnsupportedSharedPointerLayout, rpt.name, reason)
+	}
+
+	if r.engine.usesSharedMemory() {
+		return unsupported("is in memory that may be shared with other threads, its reference counts can't be updated atomically")
+	}
+
+	res, err := rpt.rawGetPointee.Call(ctx, api.EncodeU32(smartPtr))
+	if err != nil {
+		return err
+	}
+
+	if api.DecodeU32(res[0]) != r.element {
+		return unsupported("does not start with the element pointer")
+	}
+
+	released := false
+	deleteCallbackHandle := r.engine.emvalEngine.toHandle(func(ctx context.Context) error {
+		released = true
+		return nil
+	})
+
+	copyPtr, err := rpt.share(ctx, api.EncodeU32(r.element), api.EncodeI32(deleteCallbackHandle))
+	if err != nil {
+		return err
+	}
+
+	copyReference := &sharedPointerReference{
+		engine:       r.engine,
+		smartPtrType: rpt,
+	}
+
+	layoutErr := func() error {
+		memory := r.engine.mod.Memory()
+		element, ok := memory.ReadUint32Le(copyPtr)
+		if !ok || element != r.element {
+			return unsupported("does not start with the element pointer")
+		}
+
+		controlBlock, ok := memory.ReadUint32Le(copyPtr + sharedPtrControlBlockOffset)
+		if !ok || controlBlock == 0 {
+			return unsupported("does not have a control block after the element pointer")
+		}
+
+		copyReference.controlBlock = controlBlock
+		sharedOwners, err := copyReference.read(controlBlockSharedOwnersOffset)
+		if err != nil || sharedOwners != 0 {
+			return unsupported("does not have the number of owners in its control block")
+		}
+
+		weakOwners, err := copyReference.read(controlBlockWeakOwnersOffset)
+		if err != nil || weakOwners != 0 {
+			return unsupported("does not have the number of weak owners in its control block")
+		}
+
+		_, err = copyReference.lookupOnZeroSharedWeak(controlBlock)
+		if err != nil {
+			return unsupported("does not have the expected vtable in its control block")
+		}
+
+		return nil
+	}()
+
+	_, err = rpt.rawDestructor.Call(ctx, api.EncodeU32(copyPtr))
+	if err != nil {
+		return err
+	}
+
+	if layoutErr != nil {
+		return layoutErr
+	}
+
+	if !released {
+		return unsupported("did not release the object when its only owner was destructed")
+	}
+
+	return nil
+}
+
+// SharedPointer is a class instance that holds a std::shared_ptr. Unlike
+// clones of the instance, which share the same std::shared_ptr, every
+// SharedPointer that is made with Retain holds its own std::shared_ptr, so
+// it's counted by use_count() and keeps the object alive in C++ as well.
+type SharedPointer struct {
+	handle    IClassBase
+	reference *sharedPointerReference
+}
+
+// NewSharedPointer wraps a class instance that holds a std::shared_ptr. The
+// SharedPointer takes over the instance, releasing it deletes the instance.
+func NewSharedPointer(ctx context.Context, handle IClassBase) (*SharedPointer, error) {
+	if handle == nil || !handle.isValid() {
+		return nil, fmt.Errorf("invalid class instance")
+	}
+
+	record := handle.getRegisteredPtrTypeRecord()
+	if record == nil || record.ptr == 0 {
+		return nil, fmt.Errorf("cannot share a deleted object")
+	}
+
+	// Casts of the instance point to the smart pointer of the original.
+	smartPtrRecord := record
+	for smartPtrRecord.castFrom != nil {
+		smartPtrRecord = smartPtrRecord.castFrom
+	}
+
+	if smartPtrRecord.smartPtrType == nil || !smartPtrRecord.smartPtrType.isStdSharedPtr {
+		return nil, ErrNotSharedPointer
+	}
+
+	e := handle.getEngine()
+	memory := e.mod.Memory()
+	element, ok := memory.ReadUint32Le(smartPtrRecord.smartPtr)
+	if !ok {
+		return nil, fmt.Errorf("could not read %s at pointer %d", smartPtrRecord.smartPtrType.name, smartPtrRecord.smartPtr)
+	}
+
+	controlBlock, ok := memory.ReadUint32Le(smartPtrRecord.smartPtr + sharedPtrControlBlockOffset)
+	if !ok {
+		return nil, fmt.Errorf("could not read %s at pointer %d", smartPtrRecord.smartPtrType.name, smartPtrRecord.smartPtr)
+	}
+
+	reference := &sharedPointerReference{
+		engine:       e,
+		class:        handle.getClassType(),
+		ptrType:      record.ptrType,
+		ptr:          record.ptr,
+		smartPtrType: smartPtrRecord.smartPtrType,
+		element:      element,
+		controlBlock: controlBlock,
+	}
+
+	if element != 0 {
+		err := reference.checkSharedPtrLayout(e.Attach(ctx), smartPtrRecord.smartPtr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &SharedPointer{
+		handle:    handle,
+		reference: reference,
+	}, nil
+}
+
+// Handle returns the class instance.
+func (sp *SharedPointer) Handle() IClassBase {
+	return sp.handle
+}
+
+// UseCount returns the use_count() of the std::shared_ptr, the number of
+// std::shared_ptr in C++ and Go that own the object.
+func (sp *SharedPointer) UseCount(ctx context.Context) (int32, error) {
+	if sp.handle.IsInstanceDeleted(ctx, sp.handle) {
+		return 0, sp.releasedError()
+	}
+
+	return sp.reference.useCount()
+}
+
+func (sp *SharedPointer) releasedError() error {
+	return fmt.Errorf("the %s has been released", sp.reference.smartPtrType.name)
+}
+
+// Retain returns a new SharedPointer with a new instance that holds its own
+// std::shared_ptr to the object. Both have to be released.
+func (sp *SharedPointer) Retain(ctx context.Context) (*SharedPointer, error) {
+	if sp.handle.IsInstanceDeleted(ctx, sp.handle) {
+		return nil, sp.releasedError()
+	}
+
+	return sp.reference.newSharedPointer(sp.handle.getEngine().Attach(ctx))
+}
+
+// Release deletes the instance, which releases its std::shared_ptr. The
+// object is deleted when no other std::shared_ptr owns it.
+func (sp *SharedPointer) Release(ctx context.Context) error {
+	return sp.handle.DeleteInstance(sp.handle.getEngine().Attach(ctx), sp.handle)
+}
+
+// Weak returns a weak reference to the object, like a std::weak_ptr. It
+// does not keep the object alive, but it has to be released as well.
+func (sp *SharedPointer) Weak(ctx context.Context) (*WeakPointer, error) {
+	if sp.handle.IsInstanceDeleted(ctx, sp.handle) {
+		return nil, sp.releasedError()
+	}
+
+	if sp.reference.controlBlock != 0 {
+		err := sp.reference.add(controlBlockWeakOwnersOffset)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &WeakPointer{
+		reference: sp.reference,
+	}, nil
+}
+
+// WeakPointer is a weak reference to an object that is owned by
+// std::shared_ptr, like a std::weak_ptr.
+type WeakPointer struct {
+	reference *sharedPointerReference
+	released  bool
+}
+
+// Expired returns whether the object has been deleted.
+func (wp *WeakPointer) Expired(ctx context.Context) (bool, error) {
+	if wp.released {
+		return true, nil
+	}
+
+	useCount, err := wp.reference.useCount()
+	if err != nil {
+		return false, err
+	}
+	return useCount == 0, nil
+}
+
+// Lock returns a new SharedPointer to the object, or nil when the object has
+// been deleted.
+func (wp *WeakPointer) Lock(ctx context.Context) (*SharedPointer, error) {
+	expired, err := wp.Expired(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if expired {
+		return nil, nil
+	}
+
+	return wp.reference.newSharedPointer(wp.reference.engine.Attach(ctx))
+}
+
+// Release releases the weak reference. When it is the last reference to the
+// control block, the control block is deleted. Releasing it twice is a
+// no-op.
+func (wp *WeakPointer) Release(ctx context.Context) error {
+	if wp.released || wp.reference.controlBlock == 0 {
+		wp.released = true
+		return nil
+	}
+	wp.released = true
+
+	weakOwners, err := wp.reference.read(controlBlockWeakOwnersOffset)
+	if err != nil {
+		return err
+	}
+
+	if weakOwners > 0 {
+		return wp.reference.write(controlBlockWeakOwnersOffset, weakOwners-1)
+	}
+
+	// This was the last reference, let the control block delete itself
+	// through __on_zero_shared_weak().
+	onZeroSharedWeak, err := wp.reference.lookupOnZeroSharedWeak(wp.reference.controlBlock)
+	if err != nil {
+		return err
+	}
+
+	_, err = onZeroSharedWeak.Call(wp.reference.engine.Attach(ctx), api.EncodeU32(wp.reference.controlBlock))
+	return err
+}
@@ -65,7 +65,9 @@ func (e *engine) CallPublicSymbol(ctx context.Context, name string, arguments ..
 	}
 
 	ctx = e.Attach(ctx)
-	res, err := e.publicSymbols[name].fn(ctx, nil, arguments...)
+	res, err := e.observeCall(ctx, name, "", arguments, func() (any, error) {
+		return e.publicSymbols[name].fn(ctx, nil, arguments...)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error while calling embind function %s: %w", name, err)
 	}
@@ -79,6 +79,10 @@ type IType interface {
 	Type() string
 	IsClass() bool
 	IsEnum() bool
+
+	// DeclaredType returns the type string that a user type was registered
+	// with through register_type, and an empty string for other types.
+	DeclaredType() string
 }
 
 type exposedType struct {
@@ -93,16 +97,32 @@ func (et *exposedType) Name() string {
 	return et.registeredType.Name()
 }
 
+// valueType returns the type of the value of optionals, so that optional
+// classes and enums are exposed as classes and enums.
+func (et *exposedType) valueType() registeredType {
+	if optional, ok := et.registeredType.(*optionalType); ok {
+		return optional.valueType
+	}
+	return et.registeredType
+}
+
 func (et *exposedType) IsClass() bool {
-	_, ok := et.registeredType.(*registeredPointerType)
+	_, ok := et.valueType().(*registeredPointerType)
 	return ok
 }
 
 func (et *exposedType) IsEnum() bool {
-	_, ok := et.registeredType.(*enumType)
+	_, ok := et.valueType().(*enumType)
 	return ok
 }
 
+func (et *exposedType) DeclaredType() string {
+	if userType, ok := et.valueType().(*userType); ok {
+		return userType.declaredType
+	}
+	return ""
+}
+
 type registerTypeOptions struct {
 	ignoreDuplicateRegistrations bool
 }
@@ -0,0 +1,71 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/tetratelabs/wazero/api"
+)
+
+// userType is a type that is declared with EMSCRIPTEN_DECLARE_VAL_TYPE and
+// registered with register_type. On the wire it's an emscripten::val, the
+// declared type is only a TypeScript type string that describes the value.
+type userType struct {
+	emvalType
+	declaredType string
+}
+
+// GoType returns the C++ name of the type, the generator maps it to a named
+// Go type, like it does for enums.
+func (ut *userType) GoType() string {
+	return ut.name
+}
+
+func (e *engine) GetUserTypes() []IType {
+	userTypes := make([]IType, 0, len(e.registeredUserTypes))
+	for i := range e.registeredUserTypes {
+		userTypes = append(userTypes, &exposedType{registeredType: e.registeredUserTypes[i]})
+	}
+	sort.Slice(userTypes, func(i, j int) bool {
+		return userTypes[i].Name() < userTypes[j].Name()
+	})
+	return userTypes
+}
+
+var RegisterUserType = api.GoModuleFunc(func(ctx context.Context, mod api.Module, stack []uint64) {
+	engine := MustGetEngineFromContext(ctx, mod).(*engine)
+
+	rawType := api.DecodeI32(stack[0])
+	declaredType, err := engine.readCString(uint32(api.DecodeI32(stack[1])))
+	if err != nil {
+		panic(fmt.Errorf("could not read declared type: %w", err))
+	}
+
+	name, err := engine.getTypeName(ctx, rawType)
+	if err != nil {
+		panic(fmt.Errorf("could not get type name: %w", err))
+	}
+
+	registered := &userType{
+		emvalType: emvalType{
+			baseType: baseType{
+				rawType:        rawType,
+				name:           name,
+				argPackAdvance: GenericWireTypeSize,
+			},
+		},
+		declaredType: declaredType,
+	}
+
+	err = engine.registerType(rawType, registered, &registerTypeOptions{
+		ignoreDuplicateRegistrations: true,
+	})
+	if err != nil {
+		panic(fmt.Errorf("could not register: %w", err))
+	}
+
+	if _, ok := engine.registeredUserTypes[name]; !ok {
+		engine.registeredUserTypes[name] = registered
+	}
+})
@@ -0,0 +1,75 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+	"unsafe"
+)
+
+// BufferElement is the type of the elements of a Buffer.
+type BufferElement interface {
+	~int8 | ~uint8 | ~int16 | ~uint16 | ~int32 | ~uint32 | ~int64 | ~uint64 | ~float32 | ~float64
+}
+
+// Buffer is an array of elements of type T in the memory of the module,
+// which can be passed to C++ as a pointer.
+type Buffer[T BufferElement] struct {
+	engine Engine
+	ptr    uint32
+	length uint32
+}
+
+// NewBuffer allocates a Buffer of length elements in the memory of the
+// module. When the context contains a scope, the buffer is freed when the
+// scope is closed, otherwise it has to be freed with Free.
+func NewBuffer[T BufferElement](ctx context.Context, engine Engine, length uint32) (*Buffer[T], error) {
+	if length == 0 {
+		return nil, fmt.Errorf("could not allocate a buffer of 0 elements")
+	}
+
+	var element T
+	size := uint64(unsafe.Sizeof(element)) * uint64(length)
+	if size > uint64(^uint32(0)) {
+		return nil, fmt.Errorf("could not allocate a buffer of %d elements: too large", length)
+	}
+
+	ptr, err := engine.Alloc(ctx, uint32(size))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Buffer[T]{
+		engine: engine,
+		ptr:    ptr,
+		length: length,
+	}, nil
+}
+
+// Pointer returns the address of the buffer in the memory of the module.
+func (b *Buffer[T]) Pointer() uint32 {
+	return b.ptr
+}
+
+// Len returns the number of elements in the buffer.
+func (b *Buffer[T]) Len() uint32 {
+	return b.length
+}
+
+// Slice returns the elements of the buffer, without copying them. Writes to
+// the slice are visible to C++ and the other way around. The slice is only
+// valid until the memory of the module grows, which can happen on every call
+// into the module, so call Slice again after every call.
+func (b *Buffer[T]) Slice() ([]T, error) {
+	var element T
+	view, err := b.engine.ViewBytes(b.ptr, uint32(unsafe.Sizeof(element))*b.length)
+	if err != nil {
+		return nil, err
+	}
+
+	return unsafe.Slice((*T)(unsafe.Pointer(&view[0])), b.length), nil
+}
+
+// Free frees the buffer.
+func (b *Buffer[T]) Free(ctx context.Context) error {
+	return b.engine.Free(ctx, b.ptr)
+}
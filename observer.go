@@ -0,0 +1,94 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+)
+
+// Observer gets notified about every call that crosses the embind boundary.
+// Configure it with NewConfig().WithObserver(observer).
+type Observer interface {
+	internal.IObserver
+}
+
+// Span describes a finished call that crossed the embind boundary, in a form
+// that can be handed to a tracing library like OpenTelemetry.
+type Span struct {
+	Name       string
+	StartTime  time.Time
+	EndTime    time.Time
+	Attributes map[string]string
+	Err        error
+}
+
+type spanObserver struct {
+	record func(ctx context.Context, span Span)
+}
+
+// NewSpanObserver returns an Observer that calls record with a Span for every
+// call. This allows creating spans in tracing libraries without this package
+// depending on them. For OpenTelemetry, record could look like this:
+//
+//	func(ctx context.Context, span embind.Span) {
+//		_, s := tracer.Start(ctx, span.Name, trace.WithTimestamp(span.StartTime))
+//		for k, v := range span.Attributes {
+//			s.SetAttributes(attribute.String(k, v))
+//		}
+//		if span.Err != nil {
+//			s.RecordError(span.Err)
+//			s.SetStatus(codes.Error, span.Err.Error())
+//		}
+//		s.End(trace.WithTimestamp(span.EndTime))
+//	}
+func NewSpanObserver(record func(ctx context.Context, span Span)) Observer {
+	return &spanObserver{
+		record: record,
+	}
+}
+
+func (so *spanObserver) OnCall(ctx context.Context, symbol, className string, arguments []any, result any, err error, duration time.Duration) {
+	name := symbol
+	attributes := map[string]string{
+		"embind.kind":      "call",
+		"embind.symbol":    symbol,
+		"embind.arguments": fmt.Sprintf("%d", len(arguments)),
+	}
+	if className != "" {
+		name = className + "." + symbol
+		attributes["embind.class"] = className
+	}
+
+	end := time.Now()
+	so.record(ctx, Span{
+		Name:       "embind " + name,
+		StartTime:  end.Add(-duration),
+		EndTime:    end,
+		Attributes: attributes,
+		Err:        err,
+	})
+}
+
+func (so *spanObserver) OnEmvalCall(ctx context.Context, value any, method string, arguments []any, result any, err error, duration time.Duration) {
+	valueType := fmt.Sprintf("%T", value)
+	name := valueType
+	if method != "" {
+		name = valueType + "." + method
+	}
+
+	end := time.Now()
+	so.record(ctx, Span{
+		Name:      "emval " + name,
+		StartTime: end.Add(-duration),
+		EndTime:   end,
+		Attributes: map[string]string{
+			"embind.kind":      "emval",
+			"embind.type":      valueType,
+			"embind.method":    method,
+			"embind.arguments": fmt.Sprintf("%d", len(arguments)),
+		},
+		Err: err,
+	})
+}
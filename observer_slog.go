@@ -0,0 +1,52 @@
+//go:build go1.21
+
+package embind
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+type slogObserver struct {
+	logger *slog.Logger
+	level  slog.Level
+}
+
+// NewSlogObserver returns an Observer that logs every call on the given level.
+// Calls that return an error are always logged on the error level.
+func NewSlogObserver(logger *slog.Logger, level slog.Level) Observer {
+	return &slogObserver{
+		logger: logger,
+		level:  level,
+	}
+}
+
+func (so *slogObserver) log(ctx context.Context, msg string, err error, attrs ...slog.Attr) {
+	level := so.level
+	if err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.Any("error", err))
+	}
+
+	so.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+func (so *slogObserver) OnCall(ctx context.Context, symbol, className string, arguments []any, result any, err error, duration time.Duration) {
+	so.log(ctx, "embind call", err,
+		slog.String("symbol", symbol),
+		slog.String("class", className),
+		slog.Int("arguments", len(arguments)),
+		slog.Duration("duration", duration),
+	)
+}
+
+func (so *slogObserver) OnEmvalCall(ctx context.Context, value any, method string, arguments []any, result any, err error, duration time.Duration) {
+	so.log(ctx, "emval call", err,
+		slog.String("type", fmt.Sprintf("%T", value)),
+		slog.String("method", method),
+		slog.Int("arguments", len(arguments)),
+		slog.Duration("duration", duration),
+	)
+}
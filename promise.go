@@ -0,0 +1,23 @@
+package embind
+
+import (
+	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+)
+
+// Promise is the Go counterpart of a JS promise. C++ coroutines that return
+// an emscripten::val return a Promise, and C++ coroutines can co_await a
+// Promise that is created in Go with NewPromise.
+type Promise = internal.Promise
+
+// ErrPromisePending is returned by Promise.Result when the promise has not
+// been settled yet.
+var ErrPromisePending = internal.ErrPromisePending
+
+// ErrCppException is the value of Emval handles to C++ exceptions, like the
+// rejection of a coroutine that threw.
+var ErrCppException = internal.ErrCppException
+
+// NewPromise creates a new pending promise, settle it with Resolve or Reject.
+func NewPromise() *Promise {
+	return internal.NewPromise()
+}
@@ -0,0 +1,22 @@
+package embind
+
+import (
+	"context"
+
+	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+)
+
+// Scope records the class handles that are returned by calls made within it,
+// and the memory that is allocated within it, and releases them when it is
+// closed.
+type Scope = internal.Scope
+
+// NewScope returns a context that contains a new Scope. Use the returned
+// context for calls into embind, and close the scope to delete all the class
+// handles that were returned by those calls:
+//
+//	ctx, scope := embind.NewScope(ctx)
+//	defer scope.Close(ctx)
+func NewScope(ctx context.Context) (context.Context, *Scope) {
+	return internal.NewScope(ctx)
+}
@@ -0,0 +1,121 @@
+package embind
+
+import (
+	"context"
+	"fmt"
+
+	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
+)
+
+// ErrNotSharedPointer is returned by NewShared when the instance does not
+// hold a std::shared_ptr.
+var ErrNotSharedPointer = internal.ErrNotSharedPointer
+
+// ErrUnsupportedSharedPointerLayout is returned by NewShared when the
+// std::shared_ptr of the instance does not have the layout of libc++, the
+// standard library of Emscripten, that Shared relies on, or when the module
+// may share its memory with other threads.
+var ErrUnsupportedSharedPointerLayout = internal.ErrUnsupportedSharedPointerLayout
+
+// ErrUnknownSmartPointerSize is returned when an instance has to be shared
+// with a smart pointer whose smart_ptr_trait returns it by value from share.
+// Embind doesn't register the size of smart pointers, so the memory for the
+// result can't be allocated.
+var ErrUnknownSmartPointerSize = internal.ErrUnknownSmartPointerSize
+
+// Shared is a class instance that holds a std::shared_ptr and takes part in
+// its reference counting. Every Shared that is made with Retain or Lock
+// holds its own std::shared_ptr, so it keeps the object alive in C++ until
+// it's released, and it's counted by UseCount.
+type Shared[T ClassBase] struct {
+	pointer *internal.SharedPointer
+}
+
+// NewShared wraps a class instance that holds a std::shared_ptr, like the
+// instances that are returned as a std::shared_ptr by C++. The Shared takes
+// over the instance, releasing it deletes the instance.
+func NewShared[T ClassBase](ctx context.Context, instance T) (*Shared[T], error) {
+	pointer, err := internal.NewSharedPointer(ctx, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Shared[T]{
+		pointer: pointer,
+	}, nil
+}
+
+func newShared[T ClassBase](pointer *internal.SharedPointer) (*Shared[T], error) {
+	if _, ok := pointer.Handle().(T); !ok {
+		var instance T
+		return nil, fmt.Errorf("the instance is a %T, not a %T", pointer.Handle(), instance)
+	}
+
+	return &Shared[T]{
+		pointer: pointer,
+	}, nil
+}
+
+// Get returns the class instance.
+func (s *Shared[T]) Get() T {
+	return s.pointer.Handle().(T)
+}
+
+// UseCount returns the use_count() of the std::shared_ptr.
+func (s *Shared[T]) UseCount(ctx context.Context) (int32, error) {
+	return s.pointer.UseCount(ctx)
+}
+
+// Retain returns a new Shared that holds its own std::shared_ptr to the
+// object. Both have to be released.
+func (s *Shared[T]) Retain(ctx context.Context) (*Shared[T], error) {
+	pointer, err := s.pointer.Retain(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newShared[T](pointer)
+}
+
+// Release deletes the instance and releases its std::shared_ptr. The object
+// is deleted when it was the last std::shared_ptr that owned it.
+func (s *Shared[T]) Release(ctx context.Context) error {
+	return s.pointer.Release(ctx)
+}
+
+// Weak returns a weak reference to the object, like a std::weak_ptr. It
+// does not keep the object alive, but it has to be released as well.
+func (s *Shared[T]) Weak(ctx context.Context) (*Weak[T], error) {
+	pointer, err := s.pointer.Weak(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Weak[T]{
+		pointer: pointer,
+	}, nil
+}
+
+// Weak is a weak reference to an object that is owned by std::shared_ptr.
+type Weak[T ClassBase] struct {
+	pointer *internal.WeakPointer
+}
+
+// Expired returns whether the object has been deleted.
+func (w *Weak[T]) Expired(ctx context.Context) (bool, error) {
+	return w.pointer.Expired(ctx)
+}
+
+// Lock returns a new Shared to the object, or nil when the object has been
+// deleted.
+func (w *Weak[T]) Lock(ctx context.Context) (*Shared[T], error) {
+	pointer, err := w.pointer.Lock(ctx)
+	if err != nil || pointer == nil {
+		return nil, err
+	}
+	return newShared[T](pointer)
+}
+
+// Release releases the weak reference.
+func (w *Weak[T]) Release(ctx context.Context) error {
+	return w.pointer.Release(ctx)
+}
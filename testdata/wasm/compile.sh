@@ -1 +1 @@
-emcc -sERROR_ON_UNDEFINED_SYMBOLS=0 -sEXPORTED_FUNCTIONS="_free,_malloc" -sSTACK_SIZE=2MB -g classes.cpp functions.cpp constants.cpp enums.cpp structs.cpp emval.cpp embind_test.cpp test_custom_marshal.cpp test_finalization.cpp test_unsigned.cpp -o tests.wasm -lembind --no-entry
+emcc -std=c++20 -sERROR_ON_UNDEFINED_SYMBOLS=0 -sEXPORTED_FUNCTIONS="_free,_malloc" -sSTACK_SIZE=2MB -g classes.cpp functions.cpp constants.cpp enums.cpp structs.cpp emval.cpp smart_pointers.cpp null_pointers.cpp raw_pointers.cpp memory.cpp embind_test.cpp test_custom_marshal.cpp test_finalization.cpp test_unsigned.cpp -o ${1:-tests.wasm} -lembind --no-entry
@@ -0,0 +1,19 @@
+#!/bin/sh
+# Compiles the test module with every Emscripten version that is listed in
+# emscripten_versions into versions/<version>/tests.wasm, using the
+# emscripten/emsdk Docker images. Run test_versions.sh afterwards to run the
+# specs against them, the CI does both. The builds are not checked in, they
+# are reproducible from the pinned versions and would add a module per
+# version to the history on every change to the test sources.
+set -e
+cd "$(dirname "$0")"
+
+while read -r version; do
+  if [ -z "$version" ]; then
+    continue
+  fi
+
+  echo "Compiling with Emscripten $version"
+  mkdir -p "versions/$version"
+  docker run --rm -v "$(pwd):/src" -w /src -u "$(id -u):$(id -g)" "emscripten/emsdk:$version" sh ./compile.sh "versions/$version/tests.wasm"
+done < emscripten_versions
@@ -54,6 +54,13 @@ val emval_await(const val& v) {
     return v.await();
 }
 
+#if __cplusplus >= 202002L && (__EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 44))
+val emval_coro_increment(val v) {
+    int value = (co_await v).as<int>();
+    co_return val(value + 1);
+}
+#endif
+
 bool emval_is_number(const val& v) {
     return v.isNumber();
 }
@@ -82,6 +89,22 @@ val emval_array() {
     return val::array();
 }
 
+#if __EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 46)
+EMSCRIPTEN_DECLARE_VAL_TYPE(NumberList);
+
+NumberList user_type_range(int n) {
+    std::vector<int> numbers;
+    for (int i = 0; i < n; i++) {
+        numbers.push_back(i);
+    }
+    return NumberList(val::array(numbers));
+}
+
+bool user_type_is_array(NumberList numbers) {
+    return numbers.isArray();
+}
+#endif
+
 val emscripten_version() {
     std::vector<int> version_vec;
     version_vec.push_back(__EMSCRIPTEN_major__);
@@ -122,7 +145,17 @@ EMSCRIPTEN_BINDINGS(emval) {
     function("emval_array", &emval_array);
     function("emscripten_version", &emscripten_version);
 
+    #if __EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 46)
+    register_type<NumberList>("number[]");
+    function("user_type_range", &user_type_range);
+    function("user_type_is_array", &user_type_is_array);
+    #endif
+
     #if __EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 47)
     function("emval_iterator", &emval_iterator);
     #endif
-}
\ No newline at end of file
+
+    #if __cplusplus >= 202002L && (__EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 44))
+    function("emval_coro_increment", &emval_coro_increment);
+    #endif
+}
@@ -1,4 +1,6 @@
 #include <emscripten/bind.h>
+#include <emscripten/version.h>
+#include <optional>
 using namespace emscripten;
 
 bool bool_return_true() {
@@ -160,6 +162,29 @@ int function_overload_2(int x) {
     return 2;
 }
 
+#if __EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 46)
+std::optional<int> optional_int_return_optional_int(std::optional<int> in) {
+    if (!in) {
+        return {};
+    }
+    return *in * 2;
+}
+
+std::optional<std::string> optional_string_return_optional_string(std::optional<std::string> in) {
+    if (!in) {
+        return {};
+    }
+    return "Hello there " + *in;
+}
+#endif
+
+void busy_loop() {
+    volatile int i = 0;
+    while (true) {
+        i++;
+    }
+}
+
 EMSCRIPTEN_BINDINGS(functions) {
     function("bool_return_bool", &bool_return_bool);
     function("bool_return_true", &bool_return_true);
@@ -203,4 +228,13 @@ EMSCRIPTEN_BINDINGS(functions) {
 
     function("function_overload", &function_overload);
     function("function_overload", &function_overload_2);
+
+    function("busy_loop", &busy_loop);
+
+    #if __EMSCRIPTEN_major__ > 3 || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ > 1) || (__EMSCRIPTEN_major__ == 3 && __EMSCRIPTEN_minor__ == 1 && __EMSCRIPTEN_tiny__ >= 46)
+    register_optional<int>();
+    register_optional<std::string>();
+    function("optional_int_return_optional_int", &optional_int_return_optional_int);
+    function("optional_string_return_optional_string", &optional_string_return_optional_string);
+    #endif
 }
\ No newline at end of file
@@ -0,0 +1,25 @@
+#include <cstdint>
+#include <emscripten/bind.h>
+
+using namespace emscripten;
+
+int sumInt32s(uintptr_t address, int length) {
+    const int32_t* values = reinterpret_cast<const int32_t*>(address);
+    int sum = 0;
+    for (int i = 0; i < length; i++) {
+        sum += values[i];
+    }
+    return sum;
+}
+
+void fillBytes(uintptr_t address, int length, int value) {
+    uint8_t* bytes = reinterpret_cast<uint8_t*>(address);
+    for (int i = 0; i < length; i++) {
+        bytes[i] = value;
+    }
+}
+
+EMSCRIPTEN_BINDINGS(memory) {
+    function("sumInt32s", &sumInt32s);
+    function("fillBytes", &fillBytes);
+}
@@ -0,0 +1,46 @@
+#include <emscripten/bind.h>
+
+using namespace emscripten;
+
+class NullableObject {
+public:
+    NullableObject(int value) : value(value) {}
+    int getValue() const { return value; }
+
+private:
+    int value;
+};
+
+class NullableHolder {
+public:
+    NullableHolder() : object(nullptr) {}
+    NullableObject* getObject() const { return object; }
+    void setObject(NullableObject* value) { object = value; }
+
+private:
+    NullableObject* object;
+};
+
+NullableObject* getNullObject() {
+    return nullptr;
+}
+
+bool isNullObject(NullableObject* object) {
+    return object == nullptr;
+}
+
+EMSCRIPTEN_BINDINGS(null_pointers) {
+    class_<NullableObject>("NullableObject")
+        .constructor<int>()
+        .function("getValue", &NullableObject::getValue)
+        ;
+
+    class_<NullableHolder>("NullableHolder")
+        .constructor<>()
+        .function("getObject", &NullableHolder::getObject, allow_raw_pointers())
+        .function("setObject", &NullableHolder::setObject, allow_raw_pointers())
+        ;
+
+    function("getNullObject", &getNullObject, allow_raw_pointers());
+    function("isNullObject", &isNullObject, allow_raw_pointers());
+}
@@ -0,0 +1,51 @@
+#include <cstdint>
+#include <emscripten/bind.h>
+
+using namespace emscripten;
+
+int rawObjectsDestroyed = 0;
+
+class RawBase {
+public:
+    virtual ~RawBase() { ++rawObjectsDestroyed; }
+    virtual int getKind() const { return 1; }
+};
+
+class RawDerived : public RawBase {
+public:
+    int getKind() const override { return 2; }
+    int getExtra() const { return 42; }
+};
+
+RawDerived staticRawDerived;
+
+uintptr_t getStaticRawAddress() {
+    return reinterpret_cast<uintptr_t>(static_cast<RawBase*>(&staticRawDerived));
+}
+
+uintptr_t newRawAddress() {
+    return reinterpret_cast<uintptr_t>(static_cast<RawBase*>(new RawDerived()));
+}
+
+uintptr_t getRawAddress(RawBase* object) {
+    return reinterpret_cast<uintptr_t>(object);
+}
+
+int getRawObjectsDestroyed() {
+    return rawObjectsDestroyed;
+}
+
+EMSCRIPTEN_BINDINGS(raw_pointers) {
+    class_<RawBase>("RawBase")
+        .function("getKind", &RawBase::getKind)
+        ;
+
+    class_<RawDerived, base<RawBase>>("RawDerived")
+        .function("getExtra", &RawDerived::getExtra)
+        ;
+
+    function("getStaticRawAddress", &getStaticRawAddress);
+    function("newRawAddress", &newRawAddress);
+    function("getRawAddress", &getRawAddress, allow_raw_pointers());
+    function("getRawObjectsDestroyed", &getRawObjectsDestroyed);
+}
@@ -0,0 +1,306 @@
+#include <memory>
+#include <utility>
+#include <emscripten/bind.h>
+#include <emscripten/val.h>
+
+using namespace emscripten;
+
+// A smart pointer that does not share ownership at all.
+template<typename T>
+class UnsharedPtr {
+public:
+    UnsharedPtr() : ptr(nullptr) {}
+    explicit UnsharedPtr(T* ptr) : ptr(ptr) {}
+    T* get() const { return ptr; }
+
+private:
+    T* ptr;
+};
+
+// A smart pointer that keeps the reference count in the object.
+template<typename T>
+class RefPtr {
+public:
+    RefPtr() : ptr(nullptr) {}
+    explicit RefPtr(T* ptr) : ptr(ptr) {
+        if (ptr) {
+            ++ptr->refcount;
+        }
+    }
+    RefPtr(const RefPtr& other) : RefPtr(other.ptr) {}
+    RefPtr& operator=(const RefPtr& other) {
+        RefPtr(other).swap(*this);
+        return *this;
+    }
+    ~RefPtr() {
+        if (ptr && --ptr->refcount == 0) {
+            delete ptr;
+        }
+    }
+    T* get() const { return ptr; }
+    void swap(RefPtr& other) { std::swap(ptr, other.ptr); }
+
+private:
+    T* ptr;
+};
+
+// A smart pointer that is shared by emval, like std::shared_ptr, but its
+// trait returns it by value.
+template<typename T>
+class ValuePtr {
+public:
+    ValuePtr() {}
+    explicit ValuePtr(std::shared_ptr<T> ptr) : ptr(ptr) {}
+    T* get() const { return ptr.get(); }
+    void reset() { ptr.reset(); }
+
+private:
+    std::shared_ptr<T> ptr;
+};
+
+struct ValueDeleter {
+    val callback;
+    void operator()(void const*) {
+        callback();
+        callback = val::undefined();
+    }
+};
+
+namespace emscripten {
+    template<typename T>
+    struct smart_ptr_trait<UnsharedPtr<T>> {
+        typedef UnsharedPtr<T> pointer_type;
+        typedef T element_type;
+
+        static sharing_policy get_sharing_policy() {
+            return sharing_policy::NONE;
+        }
+
+        static T* get(const UnsharedPtr<T>& p) {
+            return p.get();
+        }
+
+        static UnsharedPtr<T> share(const UnsharedPtr<T>& r, T* ptr) {
+            return UnsharedPtr<T>(ptr);
+        }
+
+        static pointer_type* construct_null() {
+            return new pointer_type;
+        }
+    };
+
+    template<typename T>
+    struct smart_ptr_trait<RefPtr<T>> {
+        typedef RefPtr<T> pointer_type;
+        typedef T element_type;
+
+        static sharing_policy get_sharing_policy() {
+            return sharing_policy::INTRUSIVE;
+        }
+
+        static T* get(const RefPtr<T>& p) {
+            return p.get();
+        }
+
+        static RefPtr<T> share(const RefPtr<T>& r, T* ptr) {
+            return RefPtr<T>(ptr);
+        }
+
+        static pointer_type* construct_null() {
+            return new pointer_type;
+        }
+    };
+
+    template<typename T>
+    struct smart_ptr_trait<ValuePtr<T>> {
+        typedef ValuePtr<T> pointer_type;
+        typedef T element_type;
+
+        static sharing_policy get_sharing_policy() {
+            return sharing_policy::BY_EMVAL;
+        }
+
+        static T* get(const ValuePtr<T>& p) {
+            return p.get();
+        }
+
+        static ValuePtr<T> share(T* p, EM_VAL v) {
+            return ValuePtr<T>(std::shared_ptr<T>(p, ValueDeleter{val::take_ownership(v)}));
+        }
+
+        static pointer_type* construct_null() {
+            return new pointer_type;
+        }
+    };
+}
+
+// A smart pointer that is named like a std::shared_ptr, but that has another
+// layout than the one of libc++, so that embind.Shared has to refuse it.
+namespace std {
+    namespace __swapped {
+        template<typename T>
+        class shared_ptr {
+        public:
+            shared_ptr() {}
+            explicit shared_ptr(T* element) : refcount(new int(1)), element(element) {}
+            shared_ptr(const shared_ptr& other) : refcount(other.refcount), element(other.element) {
+                if (refcount) {
+                    ++*refcount;
+                }
+            }
+            shared_ptr& operator=(const shared_ptr&) = delete;
+            ~shared_ptr() {
+                if (refcount && --*refcount == 0) {
+                    delete refcount;
+                    delete element;
+                }
+            }
+            T* get() const { return element; }
+
+        private:
+            int* refcount = nullptr;
+            T* element = nullptr;
+        };
+    }
+}
+
+namespace emscripten {
+    template<typename T>
+    struct smart_ptr_trait<std::__swapped::shared_ptr<T>> {
+        typedef std::__swapped::shared_ptr<T> pointer_type;
+        typedef T element_type;
+
+        static sharing_policy get_sharing_policy() {
+            return sharing_policy::NONE;
+        }
+
+        static T* get(const pointer_type& p) {
+            return p.get();
+        }
+
+        static pointer_type* share(T* p, EM_VAL v) {
+            return new pointer_type(p);
+        }
+
+        static pointer_type* construct_null() {
+            return new pointer_type;
+        }
+    };
+}
+
+int sharedObjectsDestroyed = 0;
+
+class SharedObject {
+public:
+    SharedObject(int value) : value(value) {}
+    ~SharedObject() { ++sharedObjectsDestroyed; }
+    int getValue() const { return value; }
+
+private:
+    int value;
+};
+
+std::shared_ptr<SharedObject> storedSharedPtr;
+ValuePtr<SharedObject> storedValuePtr;
+
+int storeSharedPtr(std::shared_ptr<SharedObject> object) {
+    storedSharedPtr = object;
+    return object->getValue();
+}
+
+int storeValuePtr(ValuePtr<SharedObject> object) {
+    storedValuePtr = object;
+    return object.get()->getValue();
+}
+
+void releaseSharedObjects() {
+    storedSharedPtr.reset();
+    storedValuePtr.reset();
+}
+
+int getSharedObjectsDestroyed() {
+    return sharedObjectsDestroyed;
+}
+
+std::shared_ptr<SharedObject> makeSharedObject(int value) {
+    return std::make_shared<SharedObject>(value);
+}
+
+int getStoredSharedPtrUseCount() {
+    return storedSharedPtr.use_count();
+}
+
+int unsharedPtrValue(UnsharedPtr<SharedObject> object) {
+    return object.get()->getValue();
+}
+
+class IntrusiveObject {
+public:
+    IntrusiveObject(int value) : value(value) {}
+    int getValue() const { return value; }
+    int refcount = 0;
+
+private:
+    int value;
+};
+
+RefPtr<IntrusiveObject> storedRefPtr(new IntrusiveObject(42));
+
+class SwappedObject {
+public:
+    SwappedObject(int value) : value(value) {}
+    int getValue() const { return value; }
+
+private:
+    int value;
+};
+
+std::__swapped::shared_ptr<SwappedObject> makeSwappedSharedObject(int value) {
+    return std::__swapped::shared_ptr<SwappedObject>(new SwappedObject(value));
+}
+
+IntrusiveObject* getIntrusiveObject() {
+    return storedRefPtr.get();
+}
+
+int getIntrusiveObjectRefcount() {
+    return storedRefPtr.get()->refcount;
+}
+
+int refPtrValue(RefPtr<IntrusiveObject> object) {
+    return object.get()->getValue();
+}
+
+EMSCRIPTEN_BINDINGS(smart_pointers) {
+    class_<SharedObject>("SharedObject")
+        .constructor<int>()
+        .smart_ptr<std::shared_ptr<SharedObject>>("SharedObjectSharedPtr")
+        .smart_ptr<ValuePtr<SharedObject>>("SharedObjectValuePtr")
+        .smart_ptr<UnsharedPtr<SharedObject>>("SharedObjectUnsharedPtr")
+        .function("getValue", &SharedObject::getValue)
+        ;
+
+    function("storeSharedPtr", &storeSharedPtr);
+    function("storeValuePtr", &storeValuePtr);
+    function("releaseSharedObjects", &releaseSharedObjects);
+    function("getSharedObjectsDestroyed", &getSharedObjectsDestroyed);
+    function("makeSharedObject", &makeSharedObject);
+    function("getStoredSharedPtrUseCount", &getStoredSharedPtrUseCount);
+    function("unsharedPtrValue", &unsharedPtrValue);
+
+    class_<SwappedObject>("SwappedObject")
+        .smart_ptr<std::__swapped::shared_ptr<SwappedObject>>("SwappedObjectSharedPtr")
+        .function("getValue", &SwappedObject::getValue)
+        ;
+
+    function("makeSwappedSharedObject", &makeSwappedSharedObject);
+
+    class_<IntrusiveObject>("IntrusiveObject")
+        .smart_ptr<RefPtr<IntrusiveObject>>("IntrusiveObjectRefPtr")
+        .function("getValue", &IntrusiveObject::getValue)
+        ;
+
+    function("getIntrusiveObject", &getIntrusiveObject, allow_raw_pointers());
+    function("getIntrusiveObjectRefcount", &getIntrusiveObjectRefcount);
+    function("refPtrValue", &refPtrValue);
+}
@@ -0,0 +1,15 @@
+#!/bin/sh
+# Runs the specs against every build of the test module in versions/, fails
+# when there are no builds so that a missing compile step doesn't go unnoticed.
+set -e
+cd "$(dirname "$0")/../.."
+
+for wasm in testdata/wasm/versions/*/tests.wasm; do
+  if [ ! -f "$wasm" ]; then
+    echo "No builds found, run testdata/wasm/compile_versions.sh to create them" >&2
+    exit 1
+  fi
+
+  echo "Testing against $wasm"
+  EMBIND_TEST_WASM="$(pwd)/$wasm" go test -timeout 30m . ./tests/
+done
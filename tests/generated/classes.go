@@ -50,6 +50,16 @@ func (class *ClassAbstractClass) GetProperty(ctx context.Context, name string) (
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToAbstractClassWrapper returns the instance as ClassAbstractClassWrapper, or an error when it is not a ClassAbstractClassWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassAbstractClass) DowncastToAbstractClassWrapper(ctx context.Context) (*ClassAbstractClassWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "AbstractClassWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassAbstractClassWrapper), nil
+}
+
 func (class *ClassAbstractClass) AbstractMethod(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "abstractMethod")
 	if err != nil {
@@ -149,6 +159,53 @@ func ClassAbstractClassStaticImplement(e embind.Engine, ctx context.Context, arg
 	return res.(embind.ClassBase), nil
 }
 
+// ClassAbstractClassImplementation contains the methods of ClassAbstractClass that can be implemented in Go.
+type ClassAbstractClassImplementation interface {
+	AbstractMethod(ctx context.Context) (string, error)
+	ConcreteMethod(ctx context.Context) (string, error)
+	OptionalMethod(ctx context.Context, arg0 string) (string, error)
+	PassShared(ctx context.Context, arg0 embind.ClassBase) error
+	PassVal(ctx context.Context, arg0 any) error
+}
+
+// classAbstractClassImplementation maps the method calls from C++ to the methods of ClassAbstractClassImplementation.
+type classAbstractClassImplementation struct {
+	ClassAbstractClassImplementation
+}
+
+func (implementation *classAbstractClassImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	if name == "abstractMethod" && len(argTypes) == 0 {
+		return "AbstractMethod", nil
+	}
+	if name == "concreteMethod" && len(argTypes) == 0 {
+		return "ConcreteMethod", nil
+	}
+	if name == "optionalMethod" && len(argTypes) == 1 {
+		return "OptionalMethod", nil
+	}
+	if name == "passShared" && len(argTypes) == 1 {
+		return "PassShared", nil
+	}
+	if name == "passVal" && len(argTypes) == 1 {
+		return "PassVal", nil
+	}
+	return "", nil
+}
+
+// NewClassAbstractClassImplementation creates an instance of ClassAbstractClass that calls the methods of the given implementation when C++ calls them.
+func NewClassAbstractClassImplementation(e embind.Engine, ctx context.Context, implementation ClassAbstractClassImplementation) (*ClassAbstractClassWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "AbstractClass", "implement", &classAbstractClassImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassAbstractClassWrapper), nil
+}
+
 type ClassAbstractClassWithConstructor struct {
 	embind.ClassBase
 }
@@ -192,6 +249,16 @@ func (class *ClassAbstractClassWithConstructor) GetProperty(ctx context.Context,
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToAbstractClassWithConstructorWrapper returns the instance as ClassAbstractClassWithConstructorWrapper, or an error when it is not a ClassAbstractClassWithConstructorWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassAbstractClassWithConstructor) DowncastToAbstractClassWithConstructorWrapper(ctx context.Context) (*ClassAbstractClassWithConstructorWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "AbstractClassWithConstructorWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassAbstractClassWithConstructorWrapper), nil
+}
+
 func (class *ClassAbstractClassWithConstructor) AbstractMethod(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "abstractMethod")
 	if err != nil {
@@ -268,6 +335,41 @@ func ClassAbstractClassWithConstructorStaticImplement(e embind.Engine, ctx conte
 	return res.(embind.ClassBase), nil
 }
 
+// ClassAbstractClassWithConstructorImplementation contains the methods of ClassAbstractClassWithConstructor that can be implemented in Go.
+type ClassAbstractClassWithConstructorImplementation interface {
+	AbstractMethod(ctx context.Context) (string, error)
+	ConcreteMethod(ctx context.Context) (string, error)
+}
+
+// classAbstractClassWithConstructorImplementation maps the method calls from C++ to the methods of ClassAbstractClassWithConstructorImplementation.
+type classAbstractClassWithConstructorImplementation struct {
+	ClassAbstractClassWithConstructorImplementation
+}
+
+func (implementation *classAbstractClassWithConstructorImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	if name == "abstractMethod" && len(argTypes) == 0 {
+		return "AbstractMethod", nil
+	}
+	if name == "concreteMethod" && len(argTypes) == 0 {
+		return "ConcreteMethod", nil
+	}
+	return "", nil
+}
+
+// NewClassAbstractClassWithConstructorImplementation creates an instance of ClassAbstractClassWithConstructor that calls the methods of the given implementation when C++ calls them.
+func NewClassAbstractClassWithConstructorImplementation(e embind.Engine, ctx context.Context, implementation ClassAbstractClassWithConstructorImplementation) (*ClassAbstractClassWithConstructorWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "AbstractClassWithConstructor", "implement", &classAbstractClassWithConstructorImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassAbstractClassWithConstructorWrapper), nil
+}
+
 type ClassAbstractClassWithConstructorWrapper struct {
 	embind.ClassBase
 }
@@ -311,6 +413,16 @@ func (class *ClassAbstractClassWithConstructorWrapper) GetProperty(ctx context.C
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsAbstractClassWithConstructor returns the instance as its base class ClassAbstractClassWithConstructor.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassAbstractClassWithConstructorWrapper) AsAbstractClassWithConstructor(ctx context.Context) (*ClassAbstractClassWithConstructor, error) {
+	res, err := class.CastInstance(ctx, class, "AbstractClassWithConstructor")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassAbstractClassWithConstructor), nil
+}
+
 func (class *ClassAbstractClassWithConstructorWrapper) AbstractMethod(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "abstractMethod")
 	if err != nil {
@@ -435,6 +547,16 @@ func (class *ClassAbstractClassWrapper) GetProperty(ctx context.Context, name st
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsAbstractClass returns the instance as its base class ClassAbstractClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassAbstractClassWrapper) AsAbstractClass(ctx context.Context) (*ClassAbstractClass, error) {
+	res, err := class.CastInstance(ctx, class, "AbstractClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassAbstractClass), nil
+}
+
 func (class *ClassAbstractClassWrapper) AbstractMethod(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "abstractMethod")
 	if err != nil {
@@ -582,6 +704,66 @@ func (class *ClassBase) GetProperty(ctx context.Context, name string) (any, erro
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToDerived returns the instance as ClassDerived, or an error when it is not a ClassDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToDerived(ctx context.Context) (*ClassDerived, error) {
+	res, err := class.CastInstance(ctx, class, "Derived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerived), nil
+}
+
+// DowncastToDerivedThrice returns the instance as ClassDerivedThrice, or an error when it is not a ClassDerivedThrice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToDerivedThrice(ctx context.Context) (*ClassDerivedThrice, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedThrice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedThrice), nil
+}
+
+// DowncastToDerivedTwice returns the instance as ClassDerivedTwice, or an error when it is not a ClassDerivedTwice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToDerivedTwice(ctx context.Context) (*ClassDerivedTwice, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedTwice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedTwice), nil
+}
+
+// DowncastToDerivedWithMixin returns the instance as ClassDerivedWithMixin, or an error when it is not a ClassDerivedWithMixin.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToDerivedWithMixin(ctx context.Context) (*ClassDerivedWithMixin, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedWithMixin")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedWithMixin), nil
+}
+
+// DowncastToDerivedWithOffset returns the instance as ClassDerivedWithOffset, or an error when it is not a ClassDerivedWithOffset.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToDerivedWithOffset(ctx context.Context) (*ClassDerivedWithOffset, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedWithOffset")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedWithOffset), nil
+}
+
+// DowncastToMultiplyDerived returns the instance as ClassMultiplyDerived, or an error when it is not a ClassMultiplyDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase) DowncastToMultiplyDerived(ctx context.Context) (*ClassMultiplyDerived, error) {
+	res, err := class.CastInstance(ctx, class, "MultiplyDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassMultiplyDerived), nil
+}
+
 func (class *ClassBase) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -839,6 +1021,16 @@ func (class *ClassBase2) GetProperty(ctx context.Context, name string) (any, err
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToHasTwoBases returns the instance as ClassHasTwoBases, or an error when it is not a ClassHasTwoBases.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBase2) DowncastToHasTwoBases(ctx context.Context) (*ClassHasTwoBases, error) {
+	res, err := class.CastInstance(ctx, class, "HasTwoBases")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassHasTwoBases), nil
+}
+
 func (class *ClassBase2) GetPropertyField(ctx context.Context) (string, error) {
 	res, err := class.GetProperty(ctx, "field")
 	if err != nil {
@@ -911,6 +1103,26 @@ func (class *ClassBaseClass) GetProperty(ctx context.Context, name string) (any,
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToBaseClassWrapper returns the instance as ClassBaseClassWrapper, or an error when it is not a ClassBaseClassWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBaseClass) DowncastToBaseClassWrapper(ctx context.Context) (*ClassBaseClassWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "BaseClassWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBaseClassWrapper), nil
+}
+
+// DowncastToDerivedClass returns the instance as ClassDerivedClass, or an error when it is not a ClassDerivedClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBaseClass) DowncastToDerivedClass(ctx context.Context) (*ClassDerivedClass, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedClass), nil
+}
+
 func (class *ClassBaseClass) Invoke(ctx context.Context, arg0 string) error {
 	_, err := class.CallMethod(ctx, "invoke", arg0)
 	return err
@@ -966,6 +1178,37 @@ func ClassBaseClassStaticImplement(e embind.Engine, ctx context.Context, arg0 an
 	return res.(embind.ClassBase), nil
 }
 
+// ClassBaseClassImplementation contains the methods of ClassBaseClass that can be implemented in Go.
+type ClassBaseClassImplementation interface {
+	Invoke(ctx context.Context, arg0 string) error
+}
+
+// classBaseClassImplementation maps the method calls from C++ to the methods of ClassBaseClassImplementation.
+type classBaseClassImplementation struct {
+	ClassBaseClassImplementation
+}
+
+func (implementation *classBaseClassImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	if name == "invoke" && len(argTypes) == 1 {
+		return "Invoke", nil
+	}
+	return "", nil
+}
+
+// NewClassBaseClassImplementation creates an instance of ClassBaseClass that calls the methods of the given implementation when C++ calls them.
+func NewClassBaseClassImplementation(e embind.Engine, ctx context.Context, implementation ClassBaseClassImplementation) (*ClassBaseClassWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "BaseClass", "implement", &classBaseClassImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassBaseClassWrapper), nil
+}
+
 type ClassBaseClassWrapper struct {
 	embind.ClassBase
 }
@@ -1009,6 +1252,16 @@ func (class *ClassBaseClassWrapper) GetProperty(ctx context.Context, name string
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBaseClass returns the instance as its base class ClassBaseClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassBaseClassWrapper) AsBaseClass(ctx context.Context) (*ClassBaseClass, error) {
+	res, err := class.CastInstance(ctx, class, "BaseClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBaseClass), nil
+}
+
 func (class *ClassBaseClassWrapper) Invoke(ctx context.Context, arg0 string) error {
 	_, err := class.CallMethod(ctx, "invoke", arg0)
 	return err
@@ -1874,6 +2127,36 @@ func (class *ClassDerived) GetProperty(ctx context.Context, name string) (any, e
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerived) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
+// DowncastToDerivedThrice returns the instance as ClassDerivedThrice, or an error when it is not a ClassDerivedThrice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerived) DowncastToDerivedThrice(ctx context.Context) (*ClassDerivedThrice, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedThrice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedThrice), nil
+}
+
+// DowncastToDerivedTwice returns the instance as ClassDerivedTwice, or an error when it is not a ClassDerivedTwice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerived) DowncastToDerivedTwice(ctx context.Context) (*ClassDerivedTwice, error) {
+	res, err := class.CastInstance(ctx, class, "DerivedTwice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerivedTwice), nil
+}
+
 func (class *ClassDerived) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -2062,6 +2345,16 @@ func (class *ClassDerivedClass) GetProperty(ctx context.Context, name string) (a
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBaseClass returns the instance as its base class ClassBaseClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedClass) AsBaseClass(ctx context.Context) (*ClassBaseClass, error) {
+	res, err := class.CastInstance(ctx, class, "BaseClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBaseClass), nil
+}
+
 func (class *ClassDerivedClass) Invoke(ctx context.Context, arg0 string) error {
 	_, err := class.CallMethod(ctx, "invoke", arg0)
 	return err
@@ -2252,6 +2545,26 @@ func (class *ClassDerivedThrice) GetProperty(ctx context.Context, name string) (
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedThrice) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
+// AsDerived returns the instance as its base class ClassDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedThrice) AsDerived(ctx context.Context) (*ClassDerived, error) {
+	res, err := class.CastInstance(ctx, class, "Derived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerived), nil
+}
+
 func (class *ClassDerivedThrice) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -2440,6 +2753,26 @@ func (class *ClassDerivedTwice) GetProperty(ctx context.Context, name string) (a
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedTwice) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
+// AsDerived returns the instance as its base class ClassDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedTwice) AsDerived(ctx context.Context) (*ClassDerived, error) {
+	res, err := class.CastInstance(ctx, class, "Derived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassDerived), nil
+}
+
 func (class *ClassDerivedTwice) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -2628,6 +2961,16 @@ func (class *ClassDerivedWithMixin) GetProperty(ctx context.Context, name string
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedWithMixin) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
 func (class *ClassDerivedWithMixin) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -2829,6 +3172,16 @@ func (class *ClassDerivedWithOffset) GetProperty(ctx context.Context, name strin
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassDerivedWithOffset) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
 func (class *ClassDerivedWithOffset) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -3718,6 +4071,16 @@ func (class *ClassHasTwoBases) GetProperty(ctx context.Context, name string) (an
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase2 returns the instance as its base class ClassBase2.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassHasTwoBases) AsBase2(ctx context.Context) (*ClassBase2, error) {
+	res, err := class.CastInstance(ctx, class, "Base2")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase2), nil
+}
+
 func (class *ClassHasTwoBases) GetPropertyField(ctx context.Context) (string, error) {
 	res, err := class.GetProperty(ctx, "field")
 	if err != nil {
@@ -3803,6 +4166,26 @@ func (class *ClassHeldAbstractClass) GetProperty(ctx context.Context, name strin
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsPolySecondBase returns the instance as its base class ClassPolySecondBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassHeldAbstractClass) AsPolySecondBase(ctx context.Context) (*ClassPolySecondBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolySecondBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolySecondBase), nil
+}
+
+// DowncastToHeldAbstractClassWrapper returns the instance as ClassHeldAbstractClassWrapper, or an error when it is not a ClassHeldAbstractClassWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassHeldAbstractClass) DowncastToHeldAbstractClassWrapper(ctx context.Context) (*ClassHeldAbstractClassWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "HeldAbstractClassWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassHeldAbstractClassWrapper), nil
+}
+
 func (class *ClassHeldAbstractClass) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -3871,6 +4254,41 @@ func ClassHeldAbstractClassStaticImplement(e embind.Engine, ctx context.Context,
 	return res.(embind.ClassBase), nil
 }
 
+// ClassHeldAbstractClassImplementation contains the methods of ClassHeldAbstractClass that can be implemented in Go.
+type ClassHeldAbstractClassImplementation interface {
+	GetClassName(ctx context.Context) (string, error)
+	Method(ctx context.Context) error
+}
+
+// classHeldAbstractClassImplementation maps the method calls from C++ to the methods of ClassHeldAbstractClassImplementation.
+type classHeldAbstractClassImplementation struct {
+	ClassHeldAbstractClassImplementation
+}
+
+func (implementation *classHeldAbstractClassImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	if name == "getClassName" && len(argTypes) == 0 {
+		return "GetClassName", nil
+	}
+	if name == "method" && len(argTypes) == 0 {
+		return "Method", nil
+	}
+	return "", nil
+}
+
+// NewClassHeldAbstractClassImplementation creates an instance of ClassHeldAbstractClass that calls the methods of the given implementation when C++ calls them.
+func NewClassHeldAbstractClassImplementation(e embind.Engine, ctx context.Context, implementation ClassHeldAbstractClassImplementation) (*ClassHeldAbstractClassWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "HeldAbstractClass", "implement", &classHeldAbstractClassImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassHeldAbstractClassWrapper), nil
+}
+
 type ClassHeldAbstractClassWrapper struct {
 	embind.ClassBase
 }
@@ -3914,6 +4332,26 @@ func (class *ClassHeldAbstractClassWrapper) GetProperty(ctx context.Context, nam
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsHeldAbstractClass returns the instance as its base class ClassHeldAbstractClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassHeldAbstractClassWrapper) AsHeldAbstractClass(ctx context.Context) (*ClassHeldAbstractClass, error) {
+	res, err := class.CastInstance(ctx, class, "HeldAbstractClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassHeldAbstractClass), nil
+}
+
+// AsPolySecondBase returns the instance as its base class ClassPolySecondBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassHeldAbstractClassWrapper) AsPolySecondBase(ctx context.Context) (*ClassPolySecondBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolySecondBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolySecondBase), nil
+}
+
 func (class *ClassHeldAbstractClassWrapper) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -4548,10 +4986,20 @@ func (class *ClassInterface) GetProperty(ctx context.Context, name string) (any,
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
-func (class *ClassInterface) Invoke(ctx context.Context, arg0 string) error {
-	_, err := class.CallMethod(ctx, "invoke", arg0)
-	return err
-}
+// DowncastToInterfaceWrapper returns the instance as ClassInterfaceWrapper, or an error when it is not a ClassInterfaceWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassInterface) DowncastToInterfaceWrapper(ctx context.Context) (*ClassInterfaceWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "InterfaceWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassInterfaceWrapper), nil
+}
+
+func (class *ClassInterface) Invoke(ctx context.Context, arg0 string) error {
+	_, err := class.CallMethod(ctx, "invoke", arg0)
+	return err
+}
 
 func (class *ClassInterface) StaticExtend(ctx context.Context, arg0 string, arg1 any) (any, error) {
 	res, err := class.CallInstanceMethod(ctx, nil, "extend", arg0, arg1)
@@ -4603,6 +5051,37 @@ func ClassInterfaceStaticImplement(e embind.Engine, ctx context.Context, arg0 an
 	return res.(embind.ClassBase), nil
 }
 
+// ClassInterfaceImplementation contains the methods of ClassInterface that can be implemented in Go.
+type ClassInterfaceImplementation interface {
+	Invoke(ctx context.Context, arg0 string) error
+}
+
+// classInterfaceImplementation maps the method calls from C++ to the methods of ClassInterfaceImplementation.
+type classInterfaceImplementation struct {
+	ClassInterfaceImplementation
+}
+
+func (implementation *classInterfaceImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	if name == "invoke" && len(argTypes) == 1 {
+		return "Invoke", nil
+	}
+	return "", nil
+}
+
+// NewClassInterfaceImplementation creates an instance of ClassInterface that calls the methods of the given implementation when C++ calls them.
+func NewClassInterfaceImplementation(e embind.Engine, ctx context.Context, implementation ClassInterfaceImplementation) (*ClassInterfaceWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "Interface", "implement", &classInterfaceImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassInterfaceWrapper), nil
+}
+
 type ClassInterfaceWrapper struct {
 	embind.ClassBase
 }
@@ -4646,6 +5125,16 @@ func (class *ClassInterfaceWrapper) GetProperty(ctx context.Context, name string
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsInterface returns the instance as its base class ClassInterface.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassInterfaceWrapper) AsInterface(ctx context.Context) (*ClassInterface, error) {
+	res, err := class.CastInstance(ctx, class, "Interface")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassInterface), nil
+}
+
 func (class *ClassInterfaceWrapper) Invoke(ctx context.Context, arg0 string) error {
 	_, err := class.CallMethod(ctx, "invoke", arg0)
 	return err
@@ -4749,6 +5238,16 @@ func (class *ClassIntrusiveClass) GetProperty(ctx context.Context, name string)
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToIntrusiveClassWrapper returns the instance as ClassIntrusiveClassWrapper, or an error when it is not a ClassIntrusiveClassWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassIntrusiveClass) DowncastToIntrusiveClassWrapper(ctx context.Context) (*ClassIntrusiveClassWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "IntrusiveClassWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassIntrusiveClassWrapper), nil
+}
+
 func (class *ClassIntrusiveClass) StaticExtend(ctx context.Context, arg0 string, arg1 any) (any, error) {
 	res, err := class.CallInstanceMethod(ctx, nil, "extend", arg0, arg1)
 	if err != nil {
@@ -4812,6 +5311,33 @@ func NewClassIntrusiveClass(e embind.Engine, ctx context.Context) (*ClassIntrusi
 	return res.(*ClassIntrusiveClass), nil
 }
 
+// ClassIntrusiveClassImplementation contains the methods of ClassIntrusiveClass that can be implemented in Go.
+type ClassIntrusiveClassImplementation interface {
+}
+
+// classIntrusiveClassImplementation maps the method calls from C++ to the methods of ClassIntrusiveClassImplementation.
+type classIntrusiveClassImplementation struct {
+	ClassIntrusiveClassImplementation
+}
+
+func (implementation *classIntrusiveClassImplementation) MapFunction(name string, returnType string, argTypes []string) (string, error) {
+	return "", nil
+}
+
+// NewClassIntrusiveClassImplementation creates an instance of ClassIntrusiveClass that calls the methods of the given implementation when C++ calls them.
+func NewClassIntrusiveClassImplementation(e embind.Engine, ctx context.Context, implementation ClassIntrusiveClassImplementation) (*ClassIntrusiveClassWrapper, error) {
+	res, err := e.CallStaticClassMethod(ctx, "IntrusiveClass", "implement", &classIntrusiveClassImplementation{implementation})
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassIntrusiveClassWrapper), nil
+}
+
 type ClassIntrusiveClassHolder struct {
 	embind.ClassBase
 }
@@ -4929,6 +5455,16 @@ func (class *ClassIntrusiveClassWrapper) GetProperty(ctx context.Context, name s
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsIntrusiveClass returns the instance as its base class ClassIntrusiveClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassIntrusiveClassWrapper) AsIntrusiveClass(ctx context.Context) (*ClassIntrusiveClass, error) {
+	res, err := class.CastInstance(ctx, class, "IntrusiveClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassIntrusiveClass), nil
+}
+
 func (class *ClassIntrusiveClassWrapper) NotifyOnDestruction(ctx context.Context) error {
 	_, err := class.CallMethod(ctx, "notifyOnDestruction")
 	return err
@@ -4984,6 +5520,62 @@ func ClassIntrusiveClassWrapperStaticImplement(e embind.Engine, ctx context.Cont
 	return res.(embind.ClassBase), nil
 }
 
+type ClassIntrusiveObject struct {
+	embind.ClassBase
+}
+
+func (class *ClassIntrusiveObject) Clone(ctx context.Context) (*ClassIntrusiveObject, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassIntrusiveObject), nil
+}
+
+func (class *ClassIntrusiveObject) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassIntrusiveObject) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassIntrusiveObject) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassIntrusiveObject) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassIntrusiveObject) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassIntrusiveObject) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassIntrusiveObject) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+func (class *ClassIntrusiveObject) GetValue(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getValue")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
 type ClassMap_int__string_ struct {
 	embind.ClassBase
 }
@@ -5278,6 +5870,16 @@ func (class *ClassMultipleOverloads) GetProperty(ctx context.Context, name strin
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToMultipleOverloadsDerived returns the instance as ClassMultipleOverloadsDerived, or an error when it is not a ClassMultipleOverloadsDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassMultipleOverloads) DowncastToMultipleOverloadsDerived(ctx context.Context) (*ClassMultipleOverloadsDerived, error) {
+	res, err := class.CastInstance(ctx, class, "MultipleOverloadsDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassMultipleOverloadsDerived), nil
+}
+
 func (class *ClassMultipleOverloads) Func1(ctx context.Context, arg0 int32) (int32, error) {
 	res, err := class.CallMethod(ctx, "Func", arg0)
 	if err != nil {
@@ -5580,6 +6182,16 @@ func (class *ClassMultipleOverloadsDerived) GetProperty(ctx context.Context, nam
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsMultipleOverloads returns the instance as its base class ClassMultipleOverloads.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassMultipleOverloadsDerived) AsMultipleOverloads(ctx context.Context) (*ClassMultipleOverloads, error) {
+	res, err := class.CastInstance(ctx, class, "MultipleOverloads")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassMultipleOverloads), nil
+}
+
 func (class *ClassMultipleOverloadsDerived) Func1(ctx context.Context, arg0 int32) (int32, error) {
 	res, err := class.CallMethod(ctx, "Func", arg0)
 	if err != nil {
@@ -5908,6 +6520,16 @@ func (class *ClassMultiplyDerived) GetProperty(ctx context.Context, name string)
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsBase returns the instance as its base class ClassBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassMultiplyDerived) AsBase(ctx context.Context) (*ClassBase, error) {
+	res, err := class.CastInstance(ctx, class, "Base")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassBase), nil
+}
+
 func (class *ClassMultiplyDerived) GetPropertyBaseMember(ctx context.Context) (int32, error) {
 	res, err := class.GetProperty(ctx, "baseMember")
 	if err != nil {
@@ -6383,11 +7005,11 @@ func NewClassNoncopyable(e embind.Engine, ctx context.Context) (*ClassNoncopyabl
 	return res.(*ClassNoncopyable), nil
 }
 
-type ClassParentClass struct {
+type ClassNullableHolder struct {
 	embind.ClassBase
 }
 
-func (class *ClassParentClass) Clone(ctx context.Context) (*ClassParentClass, error) {
+func (class *ClassNullableHolder) Clone(ctx context.Context) (*ClassNullableHolder, error) {
 	res, err := class.CloneInstance(ctx, class)
 	if err != nil {
 		return nil, err
@@ -6395,39 +7017,39 @@ func (class *ClassParentClass) Clone(ctx context.Context) (*ClassParentClass, er
 	if res == nil {
 		return nil, nil
 	}
-	return res.(*ClassParentClass), nil
+	return res.(*ClassNullableHolder), nil
 }
 
-func (class *ClassParentClass) Delete(ctx context.Context) error {
+func (class *ClassNullableHolder) Delete(ctx context.Context) error {
 	return class.DeleteInstance(ctx, class)
 }
 
-func (class *ClassParentClass) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+func (class *ClassNullableHolder) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
 	return class.DeleteInstanceLater(ctx, class)
 }
 
-func (class *ClassParentClass) IsDeleted(ctx context.Context) bool {
+func (class *ClassNullableHolder) IsDeleted(ctx context.Context) bool {
 	return class.IsInstanceDeleted(ctx, class)
 }
 
-func (class *ClassParentClass) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+func (class *ClassNullableHolder) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
 	return class.IsAliasOfInstance(ctx, class, second)
 }
 
-func (class *ClassParentClass) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+func (class *ClassNullableHolder) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
 	return class.CallInstanceMethod(ctx, class, name, arguments...)
 }
 
-func (class *ClassParentClass) SetProperty(ctx context.Context, name string, value any) error {
+func (class *ClassNullableHolder) SetProperty(ctx context.Context, name string, value any) error {
 	return class.SetInstanceProperty(ctx, class, name, value)
 }
 
-func (class *ClassParentClass) GetProperty(ctx context.Context, name string) (any, error) {
+func (class *ClassNullableHolder) GetProperty(ctx context.Context, name string) (any, error) {
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
-func (class *ClassParentClass) GetBigClass(ctx context.Context) (embind.ClassBase, error) {
-	res, err := class.CallMethod(ctx, "getBigClass")
+func (class *ClassNullableHolder) GetObject(ctx context.Context) (embind.ClassBase, error) {
+	res, err := class.CallMethod(ctx, "getObject")
 	if err != nil {
 		return nil, err
 	}
@@ -6439,8 +7061,13 @@ func (class *ClassParentClass) GetBigClass(ctx context.Context) (embind.ClassBas
 	return res.(embind.ClassBase), nil
 }
 
-func NewClassParentClass(e embind.Engine, ctx context.Context) (*ClassParentClass, error) {
-	res, err := e.CallPublicSymbol(ctx, "ParentClass")
+func (class *ClassNullableHolder) SetObject(ctx context.Context, arg0 embind.ClassBase) error {
+	_, err := class.CallMethod(ctx, "setObject", arg0)
+	return err
+}
+
+func NewClassNullableHolder(e embind.Engine, ctx context.Context) (*ClassNullableHolder, error) {
+	res, err := e.CallPublicSymbol(ctx, "NullableHolder")
 	if err != nil {
 		return nil, err
 	}
@@ -6449,14 +7076,14 @@ func NewClassParentClass(e embind.Engine, ctx context.Context) (*ClassParentClas
 		return nil, nil
 	}
 
-	return res.(*ClassParentClass), nil
+	return res.(*ClassNullableHolder), nil
 }
 
-type ClassPolyBase struct {
+type ClassNullableObject struct {
 	embind.ClassBase
 }
 
-func (class *ClassPolyBase) Clone(ctx context.Context) (*ClassPolyBase, error) {
+func (class *ClassNullableObject) Clone(ctx context.Context) (*ClassNullableObject, error) {
 	res, err := class.CloneInstance(ctx, class)
 	if err != nil {
 		return nil, err
@@ -6464,65 +7091,52 @@ func (class *ClassPolyBase) Clone(ctx context.Context) (*ClassPolyBase, error) {
 	if res == nil {
 		return nil, nil
 	}
-	return res.(*ClassPolyBase), nil
+	return res.(*ClassNullableObject), nil
 }
 
-func (class *ClassPolyBase) Delete(ctx context.Context) error {
+func (class *ClassNullableObject) Delete(ctx context.Context) error {
 	return class.DeleteInstance(ctx, class)
 }
 
-func (class *ClassPolyBase) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+func (class *ClassNullableObject) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
 	return class.DeleteInstanceLater(ctx, class)
 }
 
-func (class *ClassPolyBase) IsDeleted(ctx context.Context) bool {
+func (class *ClassNullableObject) IsDeleted(ctx context.Context) bool {
 	return class.IsInstanceDeleted(ctx, class)
 }
 
-func (class *ClassPolyBase) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+func (class *ClassNullableObject) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
 	return class.IsAliasOfInstance(ctx, class, second)
 }
 
-func (class *ClassPolyBase) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+func (class *ClassNullableObject) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
 	return class.CallInstanceMethod(ctx, class, name, arguments...)
 }
 
-func (class *ClassPolyBase) SetProperty(ctx context.Context, name string, value any) error {
+func (class *ClassNullableObject) SetProperty(ctx context.Context, name string, value any) error {
 	return class.SetInstanceProperty(ctx, class, name, value)
 }
 
-func (class *ClassPolyBase) GetProperty(ctx context.Context, name string) (any, error) {
+func (class *ClassNullableObject) GetProperty(ctx context.Context, name string) (any, error) {
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
-func (class *ClassPolyBase) GetClassName(ctx context.Context) (string, error) {
-	res, err := class.CallMethod(ctx, "getClassName")
-	if err != nil {
-		return "", err
-	}
-
-	if res == nil {
-		return "", nil
-	}
-
-	return res.(string), nil
-}
-
-func (class *ClassPolyBase) VirtualGetClassName(ctx context.Context) (string, error) {
-	res, err := class.CallMethod(ctx, "virtualGetClassName")
+func (class *ClassNullableObject) GetValue(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getValue")
 	if err != nil {
-		return "", err
+		return int32(0), err
 	}
 
 	if res == nil {
-		return "", nil
+		return int32(0), nil
 	}
 
-	return res.(string), nil
+	return res.(int32), nil
 }
 
-func NewClassPolyBase(e embind.Engine, ctx context.Context) (*ClassPolyBase, error) {
-	res, err := e.CallPublicSymbol(ctx, "PolyBase")
+func NewClassNullableObject(e embind.Engine, ctx context.Context, arg0 int32) (*ClassNullableObject, error) {
+	res, err := e.CallPublicSymbol(ctx, "NullableObject", arg0)
 	if err != nil {
 		return nil, err
 	}
@@ -6531,14 +7145,14 @@ func NewClassPolyBase(e embind.Engine, ctx context.Context) (*ClassPolyBase, err
 		return nil, nil
 	}
 
-	return res.(*ClassPolyBase), nil
+	return res.(*ClassNullableObject), nil
 }
 
-type ClassPolyDerived struct {
+type ClassParentClass struct {
 	embind.ClassBase
 }
 
-func (class *ClassPolyDerived) Clone(ctx context.Context) (*ClassPolyDerived, error) {
+func (class *ClassParentClass) Clone(ctx context.Context) (*ClassParentClass, error) {
 	res, err := class.CloneInstance(ctx, class)
 	if err != nil {
 		return nil, err
@@ -6546,95 +7160,306 @@ func (class *ClassPolyDerived) Clone(ctx context.Context) (*ClassPolyDerived, er
 	if res == nil {
 		return nil, nil
 	}
-	return res.(*ClassPolyDerived), nil
+	return res.(*ClassParentClass), nil
 }
 
-func (class *ClassPolyDerived) Delete(ctx context.Context) error {
+func (class *ClassParentClass) Delete(ctx context.Context) error {
 	return class.DeleteInstance(ctx, class)
 }
 
-func (class *ClassPolyDerived) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+func (class *ClassParentClass) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
 	return class.DeleteInstanceLater(ctx, class)
 }
 
-func (class *ClassPolyDerived) IsDeleted(ctx context.Context) bool {
+func (class *ClassParentClass) IsDeleted(ctx context.Context) bool {
 	return class.IsInstanceDeleted(ctx, class)
 }
 
-func (class *ClassPolyDerived) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+func (class *ClassParentClass) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
 	return class.IsAliasOfInstance(ctx, class, second)
 }
 
-func (class *ClassPolyDerived) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+func (class *ClassParentClass) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
 	return class.CallInstanceMethod(ctx, class, name, arguments...)
 }
 
-func (class *ClassPolyDerived) SetProperty(ctx context.Context, name string, value any) error {
+func (class *ClassParentClass) SetProperty(ctx context.Context, name string, value any) error {
 	return class.SetInstanceProperty(ctx, class, name, value)
 }
 
-func (class *ClassPolyDerived) GetProperty(ctx context.Context, name string) (any, error) {
+func (class *ClassParentClass) GetProperty(ctx context.Context, name string) (any, error) {
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
-func (class *ClassPolyDerived) GetClassName(ctx context.Context) (string, error) {
-	res, err := class.CallMethod(ctx, "getClassName")
+func (class *ClassParentClass) GetBigClass(ctx context.Context) (embind.ClassBase, error) {
+	res, err := class.CallMethod(ctx, "getBigClass")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if res == nil {
-		return "", nil
+		return nil, nil
 	}
 
-	return res.(string), nil
+	return res.(embind.ClassBase), nil
 }
 
-func (class *ClassPolyDerived) VirtualGetClassName(ctx context.Context) (string, error) {
-	res, err := class.CallMethod(ctx, "virtualGetClassName")
+func NewClassParentClass(e embind.Engine, ctx context.Context) (*ClassParentClass, error) {
+	res, err := e.CallPublicSymbol(ctx, "ParentClass")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if res == nil {
-		return "", nil
+		return nil, nil
 	}
 
-	return res.(string), nil
+	return res.(*ClassParentClass), nil
 }
 
-func (class *ClassPolyDerived) StaticGetPtr(ctx context.Context) (embind.ClassBase, error) {
-	res, err := class.CallInstanceMethod(ctx, nil, "getPtr")
+type ClassPolyBase struct {
+	embind.ClassBase
+}
+
+func (class *ClassPolyBase) Clone(ctx context.Context) (*ClassPolyBase, error) {
+	res, err := class.CloneInstance(ctx, class)
 	if err != nil {
 		return nil, err
 	}
-
 	if res == nil {
 		return nil, nil
 	}
+	return res.(*ClassPolyBase), nil
+}
 
-	return res.(embind.ClassBase), nil
+func (class *ClassPolyBase) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
 }
-func ClassPolyDerivedStaticGetPtr(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
-	res, err := e.CallStaticClassMethod(ctx, "PolyDerived", "getPtr")
-	if err != nil {
-		return nil, err
-	}
 
-	if res == nil {
-		return nil, nil
-	}
+func (class *ClassPolyBase) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
 
-	return res.(embind.ClassBase), nil
+func (class *ClassPolyBase) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
 }
 
-func (class *ClassPolyDerived) StaticGetPtrClassName(ctx context.Context) (string, error) {
-	res, err := class.CallInstanceMethod(ctx, nil, "getPtrClassName")
-	if err != nil {
-		return "", err
-	}
+func (class *ClassPolyBase) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
 
-	if res == nil {
+func (class *ClassPolyBase) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassPolyBase) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassPolyBase) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+// DowncastToPolyDerived returns the instance as ClassPolyDerived, or an error when it is not a ClassPolyDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyBase) DowncastToPolyDerived(ctx context.Context) (*ClassPolyDerived, error) {
+	res, err := class.CastInstance(ctx, class, "PolyDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyDerived), nil
+}
+
+// DowncastToPolyDerivedThrice returns the instance as ClassPolyDerivedThrice, or an error when it is not a ClassPolyDerivedThrice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyBase) DowncastToPolyDerivedThrice(ctx context.Context) (*ClassPolyDerivedThrice, error) {
+	res, err := class.CastInstance(ctx, class, "PolyDerivedThrice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyDerivedThrice), nil
+}
+
+// DowncastToPolyMultiplyDerived returns the instance as ClassPolyMultiplyDerived, or an error when it is not a ClassPolyMultiplyDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyBase) DowncastToPolyMultiplyDerived(ctx context.Context) (*ClassPolyMultiplyDerived, error) {
+	res, err := class.CastInstance(ctx, class, "PolyMultiplyDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyMultiplyDerived), nil
+}
+
+// DowncastToPolySiblingDerived returns the instance as ClassPolySiblingDerived, or an error when it is not a ClassPolySiblingDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyBase) DowncastToPolySiblingDerived(ctx context.Context) (*ClassPolySiblingDerived, error) {
+	res, err := class.CastInstance(ctx, class, "PolySiblingDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolySiblingDerived), nil
+}
+
+func (class *ClassPolyBase) GetClassName(ctx context.Context) (string, error) {
+	res, err := class.CallMethod(ctx, "getClassName")
+	if err != nil {
+		return "", err
+	}
+
+	if res == nil {
+		return "", nil
+	}
+
+	return res.(string), nil
+}
+
+func (class *ClassPolyBase) VirtualGetClassName(ctx context.Context) (string, error) {
+	res, err := class.CallMethod(ctx, "virtualGetClassName")
+	if err != nil {
+		return "", err
+	}
+
+	if res == nil {
+		return "", nil
+	}
+
+	return res.(string), nil
+}
+
+func NewClassPolyBase(e embind.Engine, ctx context.Context) (*ClassPolyBase, error) {
+	res, err := e.CallPublicSymbol(ctx, "PolyBase")
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassPolyBase), nil
+}
+
+type ClassPolyDerived struct {
+	embind.ClassBase
+}
+
+func (class *ClassPolyDerived) Clone(ctx context.Context) (*ClassPolyDerived, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassPolyDerived), nil
+}
+
+func (class *ClassPolyDerived) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassPolyDerived) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassPolyDerived) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassPolyDerived) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassPolyDerived) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassPolyDerived) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassPolyDerived) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+// AsPolyBase returns the instance as its base class ClassPolyBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyDerived) AsPolyBase(ctx context.Context) (*ClassPolyBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolyBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyBase), nil
+}
+
+// DowncastToPolyDerivedThrice returns the instance as ClassPolyDerivedThrice, or an error when it is not a ClassPolyDerivedThrice.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyDerived) DowncastToPolyDerivedThrice(ctx context.Context) (*ClassPolyDerivedThrice, error) {
+	res, err := class.CastInstance(ctx, class, "PolyDerivedThrice")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyDerivedThrice), nil
+}
+
+func (class *ClassPolyDerived) GetClassName(ctx context.Context) (string, error) {
+	res, err := class.CallMethod(ctx, "getClassName")
+	if err != nil {
+		return "", err
+	}
+
+	if res == nil {
+		return "", nil
+	}
+
+	return res.(string), nil
+}
+
+func (class *ClassPolyDerived) VirtualGetClassName(ctx context.Context) (string, error) {
+	res, err := class.CallMethod(ctx, "virtualGetClassName")
+	if err != nil {
+		return "", err
+	}
+
+	if res == nil {
+		return "", nil
+	}
+
+	return res.(string), nil
+}
+
+func (class *ClassPolyDerived) StaticGetPtr(ctx context.Context) (embind.ClassBase, error) {
+	res, err := class.CallInstanceMethod(ctx, nil, "getPtr")
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(embind.ClassBase), nil
+}
+func ClassPolyDerivedStaticGetPtr(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
+	res, err := e.CallStaticClassMethod(ctx, "PolyDerived", "getPtr")
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(embind.ClassBase), nil
+}
+
+func (class *ClassPolyDerived) StaticGetPtrClassName(ctx context.Context) (string, error) {
+	res, err := class.CallInstanceMethod(ctx, nil, "getPtrClassName")
+	if err != nil {
+		return "", err
+	}
+
+	if res == nil {
 		return "", nil
 	}
 
@@ -6727,6 +7552,26 @@ func (class *ClassPolyDerivedThrice) GetProperty(ctx context.Context, name strin
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsPolyBase returns the instance as its base class ClassPolyBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyDerivedThrice) AsPolyBase(ctx context.Context) (*ClassPolyBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolyBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyBase), nil
+}
+
+// AsPolyDerived returns the instance as its base class ClassPolyDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyDerivedThrice) AsPolyDerived(ctx context.Context) (*ClassPolyDerived, error) {
+	res, err := class.CastInstance(ctx, class, "PolyDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyDerived), nil
+}
+
 func (class *ClassPolyDerivedThrice) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -7128,6 +7973,16 @@ func (class *ClassPolyMultiplyDerived) GetProperty(ctx context.Context, name str
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsPolyBase returns the instance as its base class ClassPolyBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolyMultiplyDerived) AsPolyBase(ctx context.Context) (*ClassPolyBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolyBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyBase), nil
+}
+
 func (class *ClassPolyMultiplyDerived) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -7210,6 +8065,26 @@ func (class *ClassPolySecondBase) GetProperty(ctx context.Context, name string)
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// DowncastToHeldAbstractClass returns the instance as ClassHeldAbstractClass, or an error when it is not a ClassHeldAbstractClass.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolySecondBase) DowncastToHeldAbstractClass(ctx context.Context) (*ClassHeldAbstractClass, error) {
+	res, err := class.CastInstance(ctx, class, "HeldAbstractClass")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassHeldAbstractClass), nil
+}
+
+// DowncastToHeldAbstractClassWrapper returns the instance as ClassHeldAbstractClassWrapper, or an error when it is not a ClassHeldAbstractClassWrapper.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolySecondBase) DowncastToHeldAbstractClassWrapper(ctx context.Context) (*ClassHeldAbstractClassWrapper, error) {
+	res, err := class.CastInstance(ctx, class, "HeldAbstractClassWrapper")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassHeldAbstractClassWrapper), nil
+}
+
 func (class *ClassPolySecondBase) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -7279,6 +8154,16 @@ func (class *ClassPolySiblingDerived) GetProperty(ctx context.Context, name stri
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+// AsPolyBase returns the instance as its base class ClassPolyBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassPolySiblingDerived) AsPolyBase(ctx context.Context) (*ClassPolyBase, error) {
+	res, err := class.CastInstance(ctx, class, "PolyBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassPolyBase), nil
+}
+
 func (class *ClassPolySiblingDerived) GetClassName(ctx context.Context) (string, error) {
 	res, err := class.CallMethod(ctx, "getClassName")
 	if err != nil {
@@ -7318,6 +8203,151 @@ func NewClassPolySiblingDerived(e embind.Engine, ctx context.Context) (*ClassPol
 	return res.(*ClassPolySiblingDerived), nil
 }
 
+type ClassRawBase struct {
+	embind.ClassBase
+}
+
+func (class *ClassRawBase) Clone(ctx context.Context) (*ClassRawBase, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassRawBase), nil
+}
+
+func (class *ClassRawBase) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassRawBase) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassRawBase) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassRawBase) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassRawBase) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassRawBase) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassRawBase) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+// DowncastToRawDerived returns the instance as ClassRawDerived, or an error when it is not a ClassRawDerived.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassRawBase) DowncastToRawDerived(ctx context.Context) (*ClassRawDerived, error) {
+	res, err := class.CastInstance(ctx, class, "RawDerived")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassRawDerived), nil
+}
+
+func (class *ClassRawBase) GetKind(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getKind")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
+type ClassRawDerived struct {
+	embind.ClassBase
+}
+
+func (class *ClassRawDerived) Clone(ctx context.Context) (*ClassRawDerived, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassRawDerived), nil
+}
+
+func (class *ClassRawDerived) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassRawDerived) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassRawDerived) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassRawDerived) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassRawDerived) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassRawDerived) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassRawDerived) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+// AsRawBase returns the instance as its base class ClassRawBase.
+// The returned instance shares the ownership of the instance like a clone, so it has to be deleted as well.
+func (class *ClassRawDerived) AsRawBase(ctx context.Context) (*ClassRawBase, error) {
+	res, err := class.CastInstance(ctx, class, "RawBase")
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ClassRawBase), nil
+}
+
+func (class *ClassRawDerived) GetExtra(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getExtra")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
+func (class *ClassRawDerived) GetKind(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getKind")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
 type ClassSecondBase struct {
 	embind.ClassBase
 }
@@ -7524,6 +8554,75 @@ func (class *ClassSecondElement) GetProperty(ctx context.Context, name string) (
 	return class.GetInstanceProperty(ctx, class, name)
 }
 
+type ClassSharedObject struct {
+	embind.ClassBase
+}
+
+func (class *ClassSharedObject) Clone(ctx context.Context) (*ClassSharedObject, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassSharedObject), nil
+}
+
+func (class *ClassSharedObject) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassSharedObject) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassSharedObject) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassSharedObject) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassSharedObject) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassSharedObject) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassSharedObject) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+func (class *ClassSharedObject) GetValue(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getValue")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
+func NewClassSharedObject(e embind.Engine, ctx context.Context, arg0 int32) (*ClassSharedObject, error) {
+	res, err := e.CallPublicSymbol(ctx, "SharedObject", arg0)
+	if err != nil {
+		return nil, err
+	}
+
+	if res == nil {
+		return nil, nil
+	}
+
+	return res.(*ClassSharedObject), nil
+}
+
 type ClassSharedPtrHolder struct {
 	embind.ClassBase
 }
@@ -8310,6 +9409,62 @@ func NewClassStringVector(e embind.Engine, ctx context.Context) (*ClassStringVec
 	return res.(*ClassStringVector), nil
 }
 
+type ClassSwappedObject struct {
+	embind.ClassBase
+}
+
+func (class *ClassSwappedObject) Clone(ctx context.Context) (*ClassSwappedObject, error) {
+	res, err := class.CloneInstance(ctx, class)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*ClassSwappedObject), nil
+}
+
+func (class *ClassSwappedObject) Delete(ctx context.Context) error {
+	return class.DeleteInstance(ctx, class)
+}
+
+func (class *ClassSwappedObject) DeleteLater(ctx context.Context) (embind.ClassBase, error) {
+	return class.DeleteInstanceLater(ctx, class)
+}
+
+func (class *ClassSwappedObject) IsDeleted(ctx context.Context) bool {
+	return class.IsInstanceDeleted(ctx, class)
+}
+
+func (class *ClassSwappedObject) IsAliasOf(ctx context.Context, second embind.ClassBase) (bool, error) {
+	return class.IsAliasOfInstance(ctx, class, second)
+}
+
+func (class *ClassSwappedObject) CallMethod(ctx context.Context, name string, arguments ...any) (any, error) {
+	return class.CallInstanceMethod(ctx, class, name, arguments...)
+}
+
+func (class *ClassSwappedObject) SetProperty(ctx context.Context, name string, value any) error {
+	return class.SetInstanceProperty(ctx, class, name, value)
+}
+
+func (class *ClassSwappedObject) GetProperty(ctx context.Context, name string) (any, error) {
+	return class.GetInstanceProperty(ctx, class, name)
+}
+
+func (class *ClassSwappedObject) GetValue(ctx context.Context) (int32, error) {
+	res, err := class.CallMethod(ctx, "getValue")
+	if err != nil {
+		return int32(0), err
+	}
+
+	if res == nil {
+		return int32(0), nil
+	}
+
+	return res.(int32), nil
+}
+
 type ClassUniquePtrLifetimeMock struct {
 	embind.ClassBase
 }
@@ -214,6 +214,9 @@ func Attach(e embind.Engine) error {
 	if err := e.RegisterClass("IntrusiveClassWrapper", &ClassIntrusiveClassWrapper{}); err != nil {
 		return err
 	}
+	if err := e.RegisterClass("IntrusiveObject", &ClassIntrusiveObject{}); err != nil {
+		return err
+	}
 	if err := e.RegisterClass("map_int__string_", &ClassMap_int__string_{}); err != nil {
 		return err
 	}
@@ -247,6 +250,12 @@ func Attach(e embind.Engine) error {
 	if err := e.RegisterClass("Noncopyable", &ClassNoncopyable{}); err != nil {
 		return err
 	}
+	if err := e.RegisterClass("NullableHolder", &ClassNullableHolder{}); err != nil {
+		return err
+	}
+	if err := e.RegisterClass("NullableObject", &ClassNullableObject{}); err != nil {
+		return err
+	}
 	if err := e.RegisterClass("ParentClass", &ClassParentClass{}); err != nil {
 		return err
 	}
@@ -280,12 +289,21 @@ func Attach(e embind.Engine) error {
 	if err := e.RegisterClass("PolySiblingDerived", &ClassPolySiblingDerived{}); err != nil {
 		return err
 	}
+	if err := e.RegisterClass("RawBase", &ClassRawBase{}); err != nil {
+		return err
+	}
+	if err := e.RegisterClass("RawDerived", &ClassRawDerived{}); err != nil {
+		return err
+	}
 	if err := e.RegisterClass("SecondBase", &ClassSecondBase{}); err != nil {
 		return err
 	}
 	if err := e.RegisterClass("SecondElement", &ClassSecondElement{}); err != nil {
 		return err
 	}
+	if err := e.RegisterClass("SharedObject", &ClassSharedObject{}); err != nil {
+		return err
+	}
 	if err := e.RegisterClass("SharedPtrHolder", &ClassSharedPtrHolder{}); err != nil {
 		return err
 	}
@@ -313,6 +331,9 @@ func Attach(e embind.Engine) error {
 	if err := e.RegisterClass("StringVector", &ClassStringVector{}); err != nil {
 		return err
 	}
+	if err := e.RegisterClass("SwappedObject", &ClassSwappedObject{}); err != nil {
+		return err
+	}
 	if err := e.RegisterClass("UniquePtrLifetimeMock", &ClassUniquePtrLifetimeMock{}); err != nil {
 		return err
 	}
@@ -1,6 +1,11 @@
 // Code generated by wazero-emscripten-embind, DO NOT EDIT.
 package generated
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 type EnumEnum uint32
 
 const (
@@ -19,6 +24,71 @@ func (enum EnumEnum) Type() any {
 	return EnumEnum(0)
 }
 
+// String returns the Embind name of the enum value.
+func (enum EnumEnum) String() string {
+	switch enum {
+	case EnumEnum_ONE:
+		return "ONE"
+	case EnumEnum_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumEnum(%d)", uint32(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumEnum) IsValid() bool {
+	switch enum {
+	case EnumEnum_ONE:
+		return true
+	case EnumEnum_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumEnum returns the enum value with the given Embind name.
+func ParseEnumEnum(name string) (EnumEnum, error) {
+	switch name {
+	case "ONE":
+		return EnumEnum_ONE, nil
+	case "TWO":
+		return EnumEnum_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumEnum", name)
+}
+
+func (enum EnumEnum) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumEnum", uint32(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumEnum) UnmarshalText(text []byte) error {
+	value, err := ParseEnumEnum(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumEnum) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumEnum) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
+
 type EnumEnumClass int8
 
 const (
@@ -37,6 +107,71 @@ func (enum EnumEnumClass) Type() any {
 	return EnumEnumClass(0)
 }
 
+// String returns the Embind name of the enum value.
+func (enum EnumEnumClass) String() string {
+	switch enum {
+	case EnumEnumClass_ONE:
+		return "ONE"
+	case EnumEnumClass_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumEnumClass(%d)", int8(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumEnumClass) IsValid() bool {
+	switch enum {
+	case EnumEnumClass_ONE:
+		return true
+	case EnumEnumClass_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumEnumClass returns the enum value with the given Embind name.
+func ParseEnumEnumClass(name string) (EnumEnumClass, error) {
+	switch name {
+	case "ONE":
+		return EnumEnumClass_ONE, nil
+	case "TWO":
+		return EnumEnumClass_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumEnumClass", name)
+}
+
+func (enum EnumEnumClass) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumEnumClass", int8(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumEnumClass) UnmarshalText(text []byte) error {
+	value, err := ParseEnumEnumClass(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumEnumClass) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumEnumClass) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
+
 type EnumNewStyle int32
 
 const (
@@ -55,6 +190,71 @@ func (enum EnumNewStyle) Type() any {
 	return EnumNewStyle(0)
 }
 
+// String returns the Embind name of the enum value.
+func (enum EnumNewStyle) String() string {
+	switch enum {
+	case EnumNewStyle_ONE:
+		return "ONE"
+	case EnumNewStyle_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumNewStyle(%d)", int32(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumNewStyle) IsValid() bool {
+	switch enum {
+	case EnumNewStyle_ONE:
+		return true
+	case EnumNewStyle_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumNewStyle returns the enum value with the given Embind name.
+func ParseEnumNewStyle(name string) (EnumNewStyle, error) {
+	switch name {
+	case "ONE":
+		return EnumNewStyle_ONE, nil
+	case "TWO":
+		return EnumNewStyle_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumNewStyle", name)
+}
+
+func (enum EnumNewStyle) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumNewStyle", int32(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumNewStyle) UnmarshalText(text []byte) error {
+	value, err := ParseEnumNewStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumNewStyle) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumNewStyle) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
+
 type EnumOldStyle uint32
 
 const (
@@ -72,3 +272,68 @@ func (enum EnumOldStyle) Values() map[string]any {
 func (enum EnumOldStyle) Type() any {
 	return EnumOldStyle(0)
 }
+
+// String returns the Embind name of the enum value.
+func (enum EnumOldStyle) String() string {
+	switch enum {
+	case EnumOldStyle_ONE:
+		return "ONE"
+	case EnumOldStyle_TWO:
+		return "TWO"
+	}
+	return fmt.Sprintf("EnumOldStyle(%d)", uint32(enum))
+}
+
+// IsValid returns whether the enum value is registered in Embind.
+func (enum EnumOldStyle) IsValid() bool {
+	switch enum {
+	case EnumOldStyle_ONE:
+		return true
+	case EnumOldStyle_TWO:
+		return true
+	}
+	return false
+}
+
+// ParseEnumOldStyle returns the enum value with the given Embind name.
+func ParseEnumOldStyle(name string) (EnumOldStyle, error) {
+	switch name {
+	case "ONE":
+		return EnumOldStyle_ONE, nil
+	case "TWO":
+		return EnumOldStyle_TWO, nil
+	}
+	return 0, fmt.Errorf("%q is not a valid EnumOldStyle", name)
+}
+
+func (enum EnumOldStyle) MarshalText() ([]byte, error) {
+	if !enum.IsValid() {
+		return nil, fmt.Errorf("%d is not a valid EnumOldStyle", uint32(enum))
+	}
+	return []byte(enum.String()), nil
+}
+
+func (enum *EnumOldStyle) UnmarshalText(text []byte) error {
+	value, err := ParseEnumOldStyle(string(text))
+	if err != nil {
+		return err
+	}
+	*enum = value
+	return nil
+}
+
+func (enum EnumOldStyle) MarshalJSON() ([]byte, error) {
+	text, err := enum.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+func (enum *EnumOldStyle) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	return enum.UnmarshalText([]byte(text))
+}
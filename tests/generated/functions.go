@@ -161,6 +161,11 @@ func BoundClass(e embind.Engine, ctx context.Context) (embind.ClassBase, error)
 	return res.(embind.ClassBase), nil
 }
 
+func Busy_loop(e embind.Engine, ctx context.Context) error {
+	_, err := e.CallPublicSymbol(ctx, "busy_loop")
+	return err
+}
+
 func C(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
 	res, err := e.CallPublicSymbol(ctx, "C")
 	if err != nil {
@@ -1645,6 +1650,11 @@ func Enum_in_enum_out(e embind.Engine, ctx context.Context, arg0 EnumNewStyle) (
 	return res.(EnumOldStyle), nil
 }
 
+func FillBytes(e embind.Engine, ctx context.Context, arg0 uint32, arg1 int32, arg2 int32) error {
+	_, err := e.CallPublicSymbol(ctx, "fillBytes", arg0, arg1, arg2)
+	return err
+}
+
 func FindPersonAtLocation(e embind.Engine, ctx context.Context, arg0 []any) (map[string]any, error) {
 	res, err := e.CallPublicSymbol(ctx, "findPersonAtLocation", arg0)
 	if err != nil {
@@ -1793,6 +1803,28 @@ func GetHasUnboundBase(e embind.Engine, ctx context.Context, arg0 any) (any, err
 	return res.(any), nil
 }
 
+func GetIntrusiveObject(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
+	res, err := e.CallPublicSymbol(ctx, "getIntrusiveObject")
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(embind.ClassBase), nil
+}
+
+func GetIntrusiveObjectRefcount(e embind.Engine, ctx context.Context) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getIntrusiveObjectRefcount")
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
 func GetNoncopyable(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
 	res, err := e.CallPublicSymbol(ctx, "getNoncopyable")
 	if err != nil {
@@ -1804,6 +1836,17 @@ func GetNoncopyable(e embind.Engine, ctx context.Context) (embind.ClassBase, err
 	return res.(embind.ClassBase), nil
 }
 
+func GetNullObject(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
+	res, err := e.CallPublicSymbol(ctx, "getNullObject")
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(embind.ClassBase), nil
+}
+
 func GetOrderedStruct(e embind.Engine, ctx context.Context) (map[string]any, error) {
 	res, err := e.CallPublicSymbol(ctx, "getOrderedStruct")
 	if err != nil {
@@ -1826,6 +1869,61 @@ func GetOrderedTuple(e embind.Engine, ctx context.Context) ([]any, error) {
 	return res.([]any), nil
 }
 
+func GetRawAddress(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (uint32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getRawAddress", arg0)
+	if err != nil {
+		return uint32(0), err
+	}
+	if res == nil {
+		return uint32(0), nil
+	}
+	return res.(uint32), nil
+}
+
+func GetRawObjectsDestroyed(e embind.Engine, ctx context.Context) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getRawObjectsDestroyed")
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
+func GetSharedObjectsDestroyed(e embind.Engine, ctx context.Context) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getSharedObjectsDestroyed")
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
+func GetStaticRawAddress(e embind.Engine, ctx context.Context) (uint32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getStaticRawAddress")
+	if err != nil {
+		return uint32(0), err
+	}
+	if res == nil {
+		return uint32(0), nil
+	}
+	return res.(uint32), nil
+}
+
+func GetStoredSharedPtrUseCount(e embind.Engine, ctx context.Context) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "getStoredSharedPtrUseCount")
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
 func GetTypeOfVal(e embind.Engine, ctx context.Context, arg0 any) (string, error) {
 	res, err := e.CallPublicSymbol(ctx, "getTypeOfVal", arg0)
 	if err != nil {
@@ -2299,6 +2397,17 @@ func IntrusiveClassWrapper(e embind.Engine, ctx context.Context) (embind.ClassBa
 	return res.(embind.ClassBase), nil
 }
 
+func IsNullObject(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (bool, error) {
+	res, err := e.CallPublicSymbol(ctx, "isNullObject", arg0)
+	if err != nil {
+		return bool(false), err
+	}
+	if res == nil {
+		return bool(false), nil
+	}
+	return res.(bool), nil
+}
+
 func Load_unsigned_char(e embind.Engine, ctx context.Context) (uint8, error) {
 	res, err := e.CallPublicSymbol(ctx, "load_unsigned_char")
 	if err != nil {
@@ -2376,6 +2485,28 @@ func Longlong_return_longlong(e embind.Engine, ctx context.Context, arg0 int64)
 	return res.(int64), nil
 }
 
+func MakeSharedObject(e embind.Engine, ctx context.Context, arg0 int32) (embind.ClassBase, error) {
+	res, err := e.CallPublicSymbol(ctx, "makeSharedObject", arg0)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(embind.ClassBase), nil
+}
+
+func MakeSwappedSharedObject(e embind.Engine, ctx context.Context, arg0 int32) (embind.ClassBase, error) {
+	res, err := e.CallPublicSymbol(ctx, "makeSwappedSharedObject", arg0)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(embind.ClassBase), nil
+}
+
 func Mallinfo(e embind.Engine, ctx context.Context) (any, error) {
 	res, err := e.CallPublicSymbol(ctx, "mallinfo")
 	if err != nil {
@@ -2486,6 +2617,17 @@ func MyClass(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
 	return res.(embind.ClassBase), nil
 }
 
+func NewRawAddress(e embind.Engine, ctx context.Context) (uint32, error) {
+	res, err := e.CallPublicSymbol(ctx, "newRawAddress")
+	if err != nil {
+		return uint32(0), err
+	}
+	if res == nil {
+		return uint32(0), nil
+	}
+	return res.(uint32), nil
+}
+
 func NoExceptClass(e embind.Engine, ctx context.Context) (embind.ClassBase, error) {
 	res, err := e.CallPublicSymbol(ctx, "NoExceptClass")
 	if err != nil {
@@ -2508,6 +2650,28 @@ func Noncopyable(e embind.Engine, ctx context.Context) (embind.ClassBase, error)
 	return res.(embind.ClassBase), nil
 }
 
+func Optional_int_return_optional_int(e embind.Engine, ctx context.Context, arg0 *int32) (*int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "optional_int_return_optional_int", arg0)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*int32), nil
+}
+
+func Optional_string_return_optional_string(e embind.Engine, ctx context.Context, arg0 *string) (*string, error) {
+	res, err := e.CallPublicSymbol(ctx, "optional_string_return_optional_string", arg0)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(*string), nil
+}
+
 func Overloaded_function1(e embind.Engine, ctx context.Context, arg0 int32) (int32, error) {
 	res, err := e.CallPublicSymbol(ctx, "overloaded_function", arg0)
 	if err != nil {
@@ -2727,6 +2891,22 @@ func PolySiblingDerived(e embind.Engine, ctx context.Context) (embind.ClassBase,
 	return res.(embind.ClassBase), nil
 }
 
+func RefPtrValue(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "refPtrValue", arg0)
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
+func ReleaseSharedObjects(e embind.Engine, ctx context.Context) error {
+	_, err := e.CallPublicSymbol(ctx, "releaseSharedObjects")
+	return err
+}
+
 func Return_Base_from_DerivedWithOffset(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (embind.ClassBase, error) {
 	res, err := e.CallPublicSymbol(ctx, "return_Base_from_DerivedWithOffset", arg0)
 	if err != nil {
@@ -2923,6 +3103,28 @@ func Std_wstring_return_std_wstring(e embind.Engine, ctx context.Context, arg0 s
 	return res.(string), nil
 }
 
+func StoreSharedPtr(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "storeSharedPtr", arg0)
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
+func StoreValuePtr(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "storeValuePtr", arg0)
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
 func Store_unsigned_char(e embind.Engine, ctx context.Context, arg0 uint8) error {
 	_, err := e.CallPublicSymbol(ctx, "store_unsigned_char", arg0)
 	return err
@@ -2998,6 +3200,17 @@ func StringVector(e embind.Engine, ctx context.Context) (embind.ClassBase, error
 	return res.(embind.ClassBase), nil
 }
 
+func SumInt32s(e embind.Engine, ctx context.Context, arg0 uint32, arg1 int32) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "sumInt32s", arg0, arg1)
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
 func Take_and_return_std_u16string(e embind.Engine, ctx context.Context, arg0 string) (string, error) {
 	res, err := e.CallPublicSymbol(ctx, "take_and_return_std_u16string", arg0)
 	if err != nil {
@@ -3124,6 +3337,17 @@ func UniquePtrToConstructor(e embind.Engine, ctx context.Context) (embind.ClassB
 	return res.(embind.ClassBase), nil
 }
 
+func UnsharedPtrValue(e embind.Engine, ctx context.Context, arg0 embind.ClassBase) (int32, error) {
+	res, err := e.CallPublicSymbol(ctx, "unsharedPtrValue", arg0)
+	if err != nil {
+		return int32(0), err
+	}
+	if res == nil {
+		return int32(0), nil
+	}
+	return res.(int32), nil
+}
+
 func Unsigned_char_to_string(e embind.Engine, ctx context.Context, arg0 uint8) (string, error) {
 	res, err := e.CallPublicSymbol(ctx, "unsigned_char_to_string", arg0)
 	if err != nil {
@@ -3168,6 +3392,28 @@ func Unsigned_short_to_string(e embind.Engine, ctx context.Context, arg0 uint16)
 	return res.(string), nil
 }
 
+func User_type_is_array(e embind.Engine, ctx context.Context, arg0 TypeNumberList) (bool, error) {
+	res, err := e.CallPublicSymbol(ctx, "user_type_is_array", arg0)
+	if err != nil {
+		return bool(false), err
+	}
+	if res == nil {
+		return bool(false), nil
+	}
+	return res.(bool), nil
+}
+
+func User_type_range(e embind.Engine, ctx context.Context, arg0 int32) (TypeNumberList, error) {
+	res, err := e.CallPublicSymbol(ctx, "user_type_range", arg0)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil {
+		return nil, nil
+	}
+	return res.(TypeNumberList), nil
+}
+
 func Ushort_return_ushort(e embind.Engine, ctx context.Context, arg0 uint16) (uint16, error) {
 	res, err := e.CallPublicSymbol(ctx, "ushort_return_ushort", arg0)
 	if err != nil {
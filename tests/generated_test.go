@@ -2,11 +2,15 @@ package tests
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"os"
 	"testing"
+	"time"
 
 	embind_external "github.com/jerbob92/wazero-emscripten-embind"
+	"github.com/jerbob92/wazero-emscripten-embind/embindtest"
 	embind "github.com/jerbob92/wazero-emscripten-embind/internal"
 	"github.com/jerbob92/wazero-emscripten-embind/tests/generated"
 	"github.com/jerbob92/wazero-emscripten-embind/types"
@@ -36,7 +40,14 @@ var wasmData []byte
 var compiledModule wazero.CompiledModule
 
 var _ = BeforeSuite(func() {
-	wasm, err := os.ReadFile("../testdata/wasm/tests.wasm")
+	// $EMBIND_TEST_WASM allows running the specs against builds of other
+	// Emscripten versions.
+	wasmPath := "../testdata/wasm/tests.wasm"
+	if path := os.Getenv("EMBIND_TEST_WASM"); path != "" {
+		wasmPath = path
+	}
+
+	wasm, err := os.ReadFile(wasmPath)
 	if err != nil {
 		Expect(err).To(BeNil())
 		return
@@ -88,6 +99,12 @@ var _ = AfterSuite(func() {
 })
 
 var _ = BeforeEach(func() {
+	instantiateWithEngineConfig(embind_external.NewConfig())
+})
+
+// instantiateWithEngineConfig instantiates the module with a new engine that
+// uses the given config.
+func instantiateWithEngineConfig(config embind.IEngineConfig) {
 	moduleConfig := wazero.NewModuleConfig().
 		WithStartFunctions("_initialize").
 		WithStdout(os.Stdout).
@@ -95,7 +112,7 @@ var _ = BeforeEach(func() {
 		WithName("")
 
 	var err error
-	engine = embind_external.CreateEngine(embind_external.NewConfig())
+	engine = embind_external.CreateEngine(config)
 	ctx = engine.Attach(ctx)
 	mod, err = runtime.InstantiateModule(ctx, compiledModule, moduleConfig)
 	if err != nil {
@@ -114,7 +131,7 @@ var _ = BeforeEach(func() {
 
 	emvalHandleCount := engine.CountEmvalHandles()
 	Expect(emvalHandleCount).To(Equal(0))
-})
+}
 
 var _ = AfterEach(func() {
 	err := engine.FlushPendingDeletes(ctx)
@@ -4072,3 +4089,657 @@ var _ = Describe("executing original embind tests", Label("library"), func() {
 		})
 	})
 })
+
+// recordingTB records the errors of the embindtest helpers.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+var _ = Describe("reporting leaks", Label("library"), func() {
+	When("handles are alive", func() {
+		It("reports live class handles and emval handles", func() {
+			report := engine.LeakReport()
+			Expect(report.Empty()).To(BeTrue())
+
+			c, err := generated.NewClassValHolder(engine, ctx, map[string]any{"foo": "bar"})
+			Expect(err).To(BeNil())
+
+			report = engine.LeakReport()
+			Expect(report.Empty()).To(BeFalse())
+			Expect(report.ClassHandles).To(HaveLen(1))
+			Expect(report.ClassHandles[0].ClassName).To(Equal("ValHolder"))
+			Expect(report.ClassHandles[0].Handle).To(Equal(c))
+			Expect(report.ClassHandles[0].RefCount).To(Equal(int32(1)))
+			Expect(report.ClassHandles[0].SmartPointerType).To(BeEmpty())
+			Expect(report.EmvalHandles).To(HaveLen(1))
+			Expect(report.EmvalHandles[0].Value).To(HaveKeyWithValue("foo", "bar"))
+
+			err = c.Delete(ctx)
+			Expect(err).To(BeNil())
+
+			report = engine.LeakReport()
+			Expect(report.Empty()).To(BeTrue())
+		})
+
+		It("reports the handles that were created since an earlier report", func() {
+			existing, err := generated.NewClassValHolder(engine, ctx, "existing")
+			Expect(err).To(BeNil())
+
+			before := engine.LeakReport()
+
+			c, err := generated.NewClassValHolder(engine, ctx, "new")
+			Expect(err).To(BeNil())
+
+			leaked := engine.LeakReport().Diff(before)
+			Expect(leaked.ClassHandles).To(HaveLen(1))
+			Expect(leaked.ClassHandles[0].Handle).To(Equal(c))
+			Expect(leaked.String()).To(ContainSubstring("class ValHolder"))
+
+			err = c.Delete(ctx)
+			Expect(err).To(BeNil())
+
+			Expect(engine.LeakReport().Diff(before).Empty()).To(BeTrue())
+
+			err = existing.Delete(ctx)
+			Expect(err).To(BeNil())
+		})
+
+		It("keeps the handle alive when it is cloned", func() {
+			c, err := generated.NewClassValHolder(engine, ctx, "value")
+			Expect(err).To(BeNil())
+
+			clone, err := c.Clone(ctx)
+			Expect(err).To(BeNil())
+			Expect(engine.LeakReport().ClassHandles).To(HaveLen(2))
+			Expect(engine.LeakReport().ClassHandles[0].RefCount).To(Equal(int32(2)))
+
+			err = c.Delete(ctx)
+			Expect(err).To(BeNil())
+			Expect(engine.LeakReport().ClassHandles).To(HaveLen(1))
+
+			err = clone.Delete(ctx)
+			Expect(err).To(BeNil())
+			Expect(engine.LeakReport().Empty()).To(BeTrue())
+		})
+
+		It("fails the test with the references and smart pointer of leaked handles", func() {
+			t := &recordingTB{}
+			var object embind_external.ClassBase
+			embindtest.ExpectNoLeaks(t, engine, func() {
+				var err error
+				object, err = generated.MakeSharedObject(engine, ctx, 2)
+				Expect(err).To(BeNil())
+			})
+
+			Expect(t.errors).To(HaveLen(1))
+			Expect(t.errors[0]).To(ContainSubstring("class SharedObject"))
+			Expect(t.errors[0]).To(ContainSubstring("references: 1"))
+			Expect(t.errors[0]).To(ContainSubstring("smart pointer: SharedObjectSharedPtr"))
+
+			err := object.DeleteInstance(ctx, object)
+			Expect(err).To(BeNil())
+
+			t = &recordingTB{}
+			embindtest.ExpectNoLeaks(t, engine, func() {})
+			Expect(t.errors).To(BeEmpty())
+		})
+
+		It("reports the smart pointer type of the handle", func() {
+			object, err := generated.MakeSharedObject(engine, ctx, 1)
+			Expect(err).To(BeNil())
+
+			report := engine.LeakReport()
+			Expect(report.ClassHandles).To(HaveLen(1))
+			Expect(report.ClassHandles[0].SmartPointerType).To(Equal("SharedObjectSharedPtr"))
+			Expect(report.String()).To(ContainSubstring("smart pointer: SharedObjectSharedPtr"))
+
+			err = object.DeleteInstance(ctx, object)
+			Expect(err).To(BeNil())
+			Expect(engine.LeakReport().Empty()).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("using scopes", Label("library"), func() {
+	When("the scope is closed", func() {
+		It("deletes the class handles that were returned within the scope", func() {
+			scopeCtx, scope := embind_external.NewScope(ctx)
+
+			c, err := generated.NewClassValHolder(engine, scopeCtx, "first")
+			Expect(err).To(BeNil())
+
+			clone, err := c.Clone(scopeCtx)
+			Expect(err).To(BeNil())
+
+			outside, err := generated.NewClassValHolder(engine, ctx, "outside")
+			Expect(err).To(BeNil())
+
+			err = scope.Close(ctx)
+			Expect(err).To(BeNil())
+
+			Expect(c.IsDeleted(ctx)).To(BeTrue())
+			Expect(clone.IsDeleted(ctx)).To(BeTrue())
+			Expect(outside.IsDeleted(ctx)).To(BeFalse())
+
+			err = outside.Delete(ctx)
+			Expect(err).To(BeNil())
+		})
+
+		It("does not delete kept handles", func() {
+			scopeCtx, scope := embind_external.NewScope(ctx)
+
+			c, err := generated.NewClassValHolder(engine, scopeCtx, "first")
+			Expect(err).To(BeNil())
+
+			kept, err := generated.NewClassValHolder(engine, scopeCtx, "kept")
+			Expect(err).To(BeNil())
+			scope.Keep(kept)
+
+			err = scope.Close(ctx)
+			Expect(err).To(BeNil())
+
+			Expect(c.IsDeleted(ctx)).To(BeTrue())
+			Expect(kept.IsDeleted(ctx)).To(BeFalse())
+
+			err = kept.Delete(ctx)
+			Expect(err).To(BeNil())
+		})
+
+		It("skips handles that were already deleted", func() {
+			scopeCtx, scope := embind_external.NewScope(ctx)
+
+			c, err := generated.NewClassValHolder(engine, scopeCtx, "first")
+			Expect(err).To(BeNil())
+
+			err = c.Delete(ctx)
+			Expect(err).To(BeNil())
+
+			err = scope.Close(ctx)
+			Expect(err).To(BeNil())
+
+			err = scope.Close(ctx)
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("flushing pending deletes", Label("library"), func() {
+	BeforeEach(func() {
+		// Replace the module that was instantiated for every test.
+		mod.Close(ctx)
+	})
+
+	When("the flush policy is FlushAfterCall", func() {
+		It("flushes after the next top-level call", func() {
+			instantiateWithEngineConfig(embind_external.NewConfig().WithFlushPolicy(embind_external.FlushAfterCall()))
+
+			v, err := generated.NewClassValHolder(engine, ctx, "value")
+			Expect(err).To(BeNil())
+
+			_, err = v.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeFalse())
+
+			_, err = generated.Emval_test_new_integer(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeTrue())
+		})
+	})
+
+	When("the flush policy is FlushOnQueueSize", func() {
+		It("flushes when the queue exceeds the size", func() {
+			instantiateWithEngineConfig(embind_external.NewConfig().WithFlushPolicy(embind_external.FlushOnQueueSize(1)))
+
+			v1, err := generated.NewClassValHolder(engine, ctx, "value1")
+			Expect(err).To(BeNil())
+			v2, err := generated.NewClassValHolder(engine, ctx, "value2")
+			Expect(err).To(BeNil())
+
+			_, err = v1.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+			Expect(v1.IsDeleted(ctx)).To(BeFalse())
+
+			_, err = v2.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+			Expect(v1.IsDeleted(ctx)).To(BeTrue())
+			Expect(v2.IsDeleted(ctx)).To(BeTrue())
+		})
+	})
+
+	When("the flush policy is FlushOnActivityAfterInterval", func() {
+		It("flushes when the interval has passed", func() {
+			instantiateWithEngineConfig(embind_external.NewConfig().WithFlushPolicy(embind_external.FlushOnActivityAfterInterval(10 * time.Millisecond)))
+
+			v, err := generated.NewClassValHolder(engine, ctx, "value")
+			Expect(err).To(BeNil())
+
+			_, err = v.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+
+			_, err = generated.Emval_test_new_integer(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeFalse())
+
+			time.Sleep(20 * time.Millisecond)
+
+			_, err = generated.Emval_test_new_integer(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeTrue())
+		})
+
+		It("starts a new interval when the queue was flushed manually", func() {
+			instantiateWithEngineConfig(embind_external.NewConfig().WithFlushPolicy(embind_external.FlushOnActivityAfterInterval(50 * time.Millisecond)))
+
+			first, err := generated.NewClassValHolder(engine, ctx, "first")
+			Expect(err).To(BeNil())
+
+			_, err = first.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+
+			err = engine.FlushPendingDeletes(ctx)
+			Expect(err).To(BeNil())
+
+			time.Sleep(30 * time.Millisecond)
+
+			second, err := generated.NewClassValHolder(engine, ctx, "second")
+			Expect(err).To(BeNil())
+
+			_, err = second.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+
+			// The interval of the first batch has passed, but not the
+			// interval of the second batch.
+			time.Sleep(30 * time.Millisecond)
+
+			_, err = generated.Emval_test_new_integer(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(second.IsDeleted(ctx)).To(BeFalse())
+
+			err = engine.FlushPendingDeletes(ctx)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	When("the flush policy is FlushManually", func() {
+		It("does not flush after a call", func() {
+			instantiateWithEngineConfig(embind_external.NewConfig().WithFlushPolicy(embind_external.FlushManually()))
+
+			v, err := generated.NewClassValHolder(engine, ctx, "value")
+			Expect(err).To(BeNil())
+
+			_, err = v.DeleteLater(ctx)
+			Expect(err).To(BeNil())
+
+			_, err = generated.Emval_test_new_integer(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeFalse())
+
+			err = engine.FlushPendingDeletes(ctx)
+			Expect(err).To(BeNil())
+			Expect(v.IsDeleted(ctx)).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("casting between base and derived classes", Label("library"), func() {
+	When("a derived class is cast to its base class", func() {
+		It("calls the methods of the base class", func() {
+			derived, err := generated.NewClassPolyDerived(engine, ctx)
+			Expect(err).To(BeNil())
+
+			base, err := derived.AsPolyBase(ctx)
+			Expect(err).To(BeNil())
+
+			className, err := base.GetClassName(ctx)
+			Expect(err).To(BeNil())
+			Expect(className).To(Equal("PolyBase"))
+
+			className, err = base.VirtualGetClassName(ctx)
+			Expect(err).To(BeNil())
+			Expect(className).To(Equal("PolyDerived"))
+
+			err = base.Delete(ctx)
+			Expect(err).To(BeNil())
+			Expect(derived.IsDeleted(ctx)).To(BeFalse())
+
+			err = derived.Delete(ctx)
+			Expect(err).To(BeNil())
+			Expect(engine.LeakReport().Empty()).To(BeTrue())
+		})
+	})
+
+	When("a base class is cast to a derived class", func() {
+		It("casts an instance of the derived class", func() {
+			derived, err := generated.NewClassPolyDerived(engine, ctx)
+			Expect(err).To(BeNil())
+
+			base, err := derived.AsPolyBase(ctx)
+			Expect(err).To(BeNil())
+
+			downcast, err := base.DowncastToPolyDerived(ctx)
+			Expect(err).To(BeNil())
+
+			className, err := downcast.GetClassName(ctx)
+			Expect(err).To(BeNil())
+			Expect(className).To(Equal("PolyDerived"))
+
+			Expect(derived.Delete(ctx)).To(BeNil())
+			Expect(base.Delete(ctx)).To(BeNil())
+			Expect(downcast.Delete(ctx)).To(BeNil())
+			Expect(engine.LeakReport().Empty()).To(BeTrue())
+		})
+
+		It("returns an error when the instance is of another class", func() {
+			sibling, err := generated.NewClassPolySiblingDerived(engine, ctx)
+			Expect(err).To(BeNil())
+
+			base, err := sibling.AsPolyBase(ctx)
+			Expect(err).To(BeNil())
+
+			downcast, err := base.DowncastToPolyDerived(ctx)
+			Expect(err).To(Not(BeNil()))
+			Expect(err.Error()).To(ContainSubstring("cannot downcast PolyBase to PolyDerived, the instance is PolySiblingDerived"))
+			Expect(downcast).To(BeNil())
+
+			Expect(base.Delete(ctx)).To(BeNil())
+			Expect(sibling.Delete(ctx)).To(BeNil())
+		})
+	})
+})
+
+type abstractClassImplementation struct {
+	prefix string
+}
+
+func (impl *abstractClassImplementation) AbstractMethod(ctx context.Context) (string, error) {
+	return impl.prefix + "abstract", nil
+}
+
+func (impl *abstractClassImplementation) ConcreteMethod(ctx context.Context) (string, error) {
+	return impl.prefix + "concrete", nil
+}
+
+func (impl *abstractClassImplementation) OptionalMethod(ctx context.Context, arg0 string) (string, error) {
+	return impl.prefix + "optional" + arg0, nil
+}
+
+func (impl *abstractClassImplementation) PassShared(ctx context.Context, arg0 embind_external.ClassBase) error {
+	return nil
+}
+
+func (impl *abstractClassImplementation) PassVal(ctx context.Context, arg0 any) error {
+	return nil
+}
+
+var _ = Describe("implementing classes in Go", Label("library"), func() {
+	When("the class is implemented with a Go implementation", func() {
+		It("calls the Go methods from C++", func() {
+			impl, err := generated.NewClassAbstractClassImplementation(engine, ctx, &abstractClassImplementation{prefix: "go "})
+			Expect(err).To(BeNil())
+
+			res, err := generated.CallAbstractMethod(engine, ctx, impl)
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal("go abstract"))
+
+			res, err = generated.CallOptionalMethod(engine, ctx, impl, "foo")
+			Expect(err).To(BeNil())
+			Expect(res).To(Equal("go optionalfoo"))
+
+			err = impl.Delete(ctx)
+			Expect(err).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("using generated enums", Label("library"), func() {
+	When("converting enums to and from strings", func() {
+		It("uses the Embind names", func() {
+			Expect(generated.EnumEnum_TWO.String()).To(Equal("TWO"))
+			Expect(generated.EnumEnum(5).String()).To(Equal("EnumEnum(5)"))
+			Expect(generated.EnumEnum_ONE.IsValid()).To(BeTrue())
+			Expect(generated.EnumEnum(5).IsValid()).To(BeFalse())
+
+			value, err := generated.ParseEnumEnum("TWO")
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(generated.EnumEnum_TWO))
+
+			_, err = generated.ParseEnumEnum("THREE")
+			Expect(err).To(Not(BeNil()))
+		})
+
+		It("marshals to and from JSON", func() {
+			data, err := json.Marshal(map[string]generated.EnumEnum{"value": generated.EnumEnum_ONE})
+			Expect(err).To(BeNil())
+			Expect(string(data)).To(Equal(`{"value":"ONE"}`))
+
+			var value map[string]generated.EnumEnum
+			err = json.Unmarshal([]byte(`{"value":"TWO"}`), &value)
+			Expect(err).To(BeNil())
+			Expect(value["value"]).To(Equal(generated.EnumEnum_TWO))
+
+			_, err = json.Marshal(generated.EnumEnum(5))
+			Expect(err).To(Not(BeNil()))
+		})
+	})
+
+	When("passing an enum value that is not registered", func() {
+		It("returns an error", func() {
+			_, err := generated.Emval_test_take_and_return_Enum(engine, ctx, generated.EnumEnum(5))
+			Expect(err).To(Not(BeNil()))
+			if err != nil {
+				Expect(err.Error()).To(ContainSubstring("5 is not a valid value of enum Enum"))
+			}
+		})
+	})
+})
+
+var _ = Describe("using optionals (from version 3.1.46)", Label("library"), func() {
+	When("the optional has a value", func() {
+		It("passes and returns pointers", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+				return
+			}
+
+			value := int32(21)
+			res, err := generated.Optional_int_return_optional_int(engine, ctx, &value)
+			Expect(err).To(BeNil())
+			Expect(res).To(Not(BeNil()))
+			Expect(*res).To(Equal(int32(42)))
+
+			name := "embind"
+			stringRes, err := generated.Optional_string_return_optional_string(engine, ctx, &name)
+			Expect(err).To(BeNil())
+			Expect(stringRes).To(Not(BeNil()))
+			Expect(*stringRes).To(Equal("Hello there embind"))
+		})
+	})
+
+	When("the optional is empty", func() {
+		It("passes and returns nil", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+				return
+			}
+
+			res, err := generated.Optional_int_return_optional_int(engine, ctx, nil)
+			Expect(err).To(BeNil())
+			Expect(res).To(BeNil())
+
+			stringRes, err := generated.Optional_string_return_optional_string(engine, ctx, nil)
+			Expect(err).To(BeNil())
+			Expect(stringRes).To(BeNil())
+		})
+	})
+})
+
+var _ = Describe("using user types (from version 3.1.46)", Label("library"), func() {
+	When("the function returns a user type", func() {
+		It("returns the value as the named type", func() {
+			version, err := engine.CallPublicSymbol(ctx, "emscripten_version")
+			Expect(err).To(BeNil())
+			if !isSupportedByEmscriptenVersion(version.([]any), 3, 1, 46) {
+				return
+			}
+
+			numbers, err := generated.User_type_range(engine, ctx, 3)
+			Expect(err).To(BeNil())
+			Expect(numbers).To(Equal(generated.TypeNumberList([]any{int32(0), int32(1), int32(2)})))
+
+			isArray, err := generated.User_type_is_array(engine, ctx, numbers)
+			Expect(err).To(BeNil())
+			Expect(isArray).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("sharing raw pointers with smart pointers", Label("library"), func() {
+	When("the smart pointer is a std::shared_ptr", func() {
+		It("keeps the object alive until C++ releases it", func() {
+			destroyed, err := generated.GetSharedObjectsDestroyed(engine, ctx)
+			Expect(err).To(BeNil())
+
+			object, err := generated.NewClassSharedObject(engine, ctx, 3)
+			Expect(err).To(BeNil())
+
+			value, err := generated.StoreSharedPtr(engine, ctx, object)
+			Expect(err).To(BeNil())
+			Expect(value).To(Equal(int32(3)))
+
+			err = object.Delete(ctx)
+			Expect(err).To(BeNil())
+
+			err = generated.ReleaseSharedObjects(engine, ctx)
+			Expect(err).To(BeNil())
+
+			destroyedAfterRelease, err := generated.GetSharedObjectsDestroyed(engine, ctx)
+			Expect(err).To(BeNil())
+			Expect(destroyedAfterRelease).To(Equal(destroyed + 1))
+		})
+	})
+})
+
+var _ = Describe("sharing std::shared_ptr with Go", Label("library"), func() {
+	It("counts the Go handles as owners", func() {
+		object, err := generated.MakeSharedObject(engine, ctx, 4)
+		Expect(err).To(BeNil())
+
+		shared, err := embind_external.NewShared(ctx, object.(*generated.ClassSharedObject))
+		Expect(err).To(BeNil())
+		Expect(shared.Get().GetValue(ctx)).To(Equal(int32(4)))
+
+		retained, err := shared.Retain(ctx)
+		Expect(err).To(BeNil())
+		Expect(retained.UseCount(ctx)).To(Equal(int32(2)))
+
+		weak, err := retained.Weak(ctx)
+		Expect(err).To(BeNil())
+
+		Expect(shared.Release(ctx)).To(Succeed())
+		Expect(retained.Release(ctx)).To(Succeed())
+		Expect(weak.Expired(ctx)).To(BeTrue())
+		Expect(weak.Release(ctx)).To(Succeed())
+	})
+
+	It("checks the layout without releasing the object", func() {
+		destroyed, err := generated.GetSharedObjectsDestroyed(engine, ctx)
+		Expect(err).To(BeNil())
+
+		object, err := generated.MakeSharedObject(engine, ctx, 5)
+		Expect(err).To(BeNil())
+
+		shared, err := embind_external.NewShared(ctx, object.(*generated.ClassSharedObject))
+		Expect(err).To(BeNil())
+		Expect(shared.UseCount(ctx)).To(Equal(int32(1)))
+		Expect(shared.Get().GetValue(ctx)).To(Equal(int32(5)))
+		Expect(generated.GetSharedObjectsDestroyed(engine, ctx)).To(Equal(destroyed))
+
+		Expect(shared.Release(ctx)).To(Succeed())
+		Expect(generated.GetSharedObjectsDestroyed(engine, ctx)).To(Equal(destroyed + 1))
+	})
+})
+
+var _ = Describe("handling null pointers", Label("library"), func() {
+	It("returns a typed nil for a null pointer", func() {
+		holder, err := generated.NewClassNullableHolder(engine, ctx)
+		Expect(err).To(BeNil())
+		defer holder.Delete(ctx)
+
+		object, err := holder.GetObject(ctx)
+		Expect(err).To(BeNil())
+		Expect(object).To(BeNil())
+
+		object, err = generated.GetNullObject(engine, ctx)
+		Expect(err).To(BeNil())
+		Expect(object).To(BeNil())
+
+		isNull, err := generated.IsNullObject(engine, ctx, nil)
+		Expect(err).To(BeNil())
+		Expect(isNull).To(BeTrue())
+	})
+})
+
+var _ = Describe("wrapping raw pointers", Label("library"), func() {
+	It("wraps an address as its dynamic type", func() {
+		address, err := generated.GetStaticRawAddress(engine, ctx)
+		Expect(err).To(BeNil())
+
+		object, err := engine.WrapPointer(ctx, "RawBase", address, false)
+		Expect(err).To(BeNil())
+
+		derived, ok := object.(*generated.ClassRawDerived)
+		Expect(ok).To(BeTrue())
+		defer derived.Delete(ctx)
+
+		extra, err := derived.GetExtra(ctx)
+		Expect(err).To(BeNil())
+		Expect(extra).To(Equal(int32(42)))
+
+		rawAddress, err := generated.GetRawAddress(engine, ctx, derived)
+		Expect(err).To(BeNil())
+		Expect(rawAddress).To(Equal(derived.Pointer()))
+	})
+})
+
+var _ = Describe("passing buffers", Label("library"), func() {
+	It("passes the pointer of a buffer", func() {
+		scopeCtx, scope := embind_external.NewScope(ctx)
+		defer scope.Close(ctx)
+
+		buffer, err := embind_external.NewBuffer[int32](scopeCtx, engine, 3)
+		Expect(err).To(BeNil())
+
+		values, err := buffer.Slice()
+		Expect(err).To(BeNil())
+		copy(values, []int32{5, 6, 7})
+
+		sum, err := generated.SumInt32s(engine, ctx, buffer.Pointer(), int32(buffer.Len()))
+		Expect(err).To(BeNil())
+		Expect(sum).To(Equal(int32(18)))
+	})
+})
+
+func isSupportedByEmscriptenVersion(version []any, major, minor, tiny int32) bool {
+	currentMajor := version[0].(int32)
+	currentMinor := version[1].(int32)
+	currentTiny := version[2].(int32)
+
+	if currentMajor != major {
+		return currentMajor > major
+	}
+
+	if currentMinor != minor {
+		return currentMinor > minor
+	}
+
+	return currentTiny >= tiny
+}
@@ -2,6 +2,8 @@ package embind
 
 import (
 	"fmt"
+	"strings"
+
 	internal "github.com/jerbob92/wazero-emscripten-embind/internal"
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
@@ -9,16 +11,26 @@ import (
 
 type wazeroEngine struct {
 	internal.IEngine
-	config internal.IEngineConfig
+	config      internal.IEngineConfig
+	abiVariants map[string]string
 }
 
 func (we *wazeroEngine) NewFunctionExporterForModule(guest wazero.CompiledModule) FunctionExporter {
 	return &functionExporter{
+		engine: we,
 		config: we.config,
 		guest:  guest,
 	}
 }
 
+func (we *wazeroEngine) ABIVariants() map[string]string {
+	variants := make(map[string]string, len(we.abiVariants))
+	for name, variant := range we.abiVariants {
+		variants[name] = variant
+	}
+	return variants
+}
+
 // FunctionExporter configures the functions in the "env" module used by
 // Emscripten embind.
 type FunctionExporter interface {
@@ -28,6 +40,7 @@ type FunctionExporter interface {
 }
 
 type functionExporter struct {
+	engine *wazeroEngine
 	config internal.IEngineConfig
 	guest  wazero.CompiledModule
 }
@@ -40,6 +53,86 @@ func (e unexportedFunctionError) Error() string {
 	return fmt.Sprintf("you need to export the \"%s\" function to make embind work, you can do this using the \"EXPORTED_FUNCTIONS\" option in Emscripten during compilation, you will need to prepend exports with an underscore, so you have to add \"_%s\" to the list", e.name, e.name)
 }
 
+// UnsupportedEmscriptenVersionError is returned by ExportFunctions when the
+// module imports an Embind function with a signature that is not known, which
+// means that the module has been compiled with an Emscripten version that is
+// not supported.
+type UnsupportedEmscriptenVersionError struct {
+	// Name is the name of the imported function.
+	Name string
+
+	// Signature is the signature of the imported function.
+	Signature string
+
+	// SupportedSignature is the signature that is supported, empty when the
+	// function is not supported at all.
+	SupportedSignature string
+}
+
+func (e UnsupportedEmscriptenVersionError) Error() string {
+	if e.SupportedSignature == "" {
+		return fmt.Sprintf("unsupported Emscripten version: the module imports \"%s\" with signature %s, which is not supported by wazero-emscripten-embind", e.Name, e.Signature)
+	}
+	return fmt.Sprintf("unsupported Emscripten version: the module imports \"%s\" with signature %s, but only %s is supported by wazero-emscripten-embind", e.Name, e.Signature, e.SupportedSignature)
+}
+
+func formatSignature(params, results []api.ValueType) string {
+	formatTypes := func(types []api.ValueType) string {
+		names := make([]string, len(types))
+		for i := range types {
+			names[i] = api.ValueTypeName(types[i])
+		}
+		return "(" + strings.Join(names, ", ") + ")"
+	}
+	return formatTypes(params) + " -> " + formatTypes(results)
+}
+
+// signatureRecordingBuilder records the signatures of the exported host
+// functions, so that they can be validated against the imports of the module.
+type signatureRecordingBuilder struct {
+	wazero.HostModuleBuilder
+	signatures map[string]string
+}
+
+func (rb *signatureRecordingBuilder) NewFunctionBuilder() wazero.HostFunctionBuilder {
+	return &signatureRecordingFunctionBuilder{
+		HostFunctionBuilder: rb.HostModuleBuilder.NewFunctionBuilder(),
+		signatures:          rb.signatures,
+	}
+}
+
+type signatureRecordingFunctionBuilder struct {
+	wazero.HostFunctionBuilder
+	signatures map[string]string
+	signature  string
+}
+
+func (rfb *signatureRecordingFunctionBuilder) WithGoModuleFunction(fn api.GoModuleFunction, params, results []api.ValueType) wazero.HostFunctionBuilder {
+	rfb.signature = formatSignature(params, results)
+	rfb.HostFunctionBuilder = rfb.HostFunctionBuilder.WithGoModuleFunction(fn, params, results)
+	return rfb
+}
+
+func (rfb *signatureRecordingFunctionBuilder) WithName(name string) wazero.HostFunctionBuilder {
+	rfb.HostFunctionBuilder = rfb.HostFunctionBuilder.WithName(name)
+	return rfb
+}
+
+func (rfb *signatureRecordingFunctionBuilder) WithParameterNames(names ...string) wazero.HostFunctionBuilder {
+	rfb.HostFunctionBuilder = rfb.HostFunctionBuilder.WithParameterNames(names...)
+	return rfb
+}
+
+func (rfb *signatureRecordingFunctionBuilder) WithResultNames(names ...string) wazero.HostFunctionBuilder {
+	rfb.HostFunctionBuilder = rfb.HostFunctionBuilder.WithResultNames(names...)
+	return rfb
+}
+
+func (rfb *signatureRecordingFunctionBuilder) Export(name string) wazero.HostModuleBuilder {
+	rfb.signatures[name] = rfb.signature
+	return rfb.HostFunctionBuilder.Export(name)
+}
+
 func (e functionExporter) GetImportedFunction(name string) api.FunctionDefinition {
 	importedFunctions := e.guest.ImportedFunctions()
 	for i := range importedFunctions {
@@ -53,7 +146,15 @@ func (e functionExporter) GetImportedFunction(name string) api.FunctionDefinitio
 }
 
 // ExportFunctions implements FunctionExporter.ExportFunctions
-func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
+func (e functionExporter) ExportFunctions(moduleBuilder wazero.HostModuleBuilder) error {
+	b := &signatureRecordingBuilder{
+		HostModuleBuilder: moduleBuilder,
+		signatures:        map[string]string{},
+	}
+
+	// The variants of the imports that changed between Emscripten versions.
+	abiVariants := map[string]string{}
+
 	// First validate whether required functions are available.
 	requiredFunctions := []string{"free", "malloc", "__getTypeName"}
 	exportedFunctions := e.guest.ExportedFunctions()
@@ -84,12 +185,14 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		// before the size was part of the registration.
 		boolHasSizeArgument := len(importedEmbindRegisterBool.ParamTypes()) == 5
 		if boolHasSizeArgument {
+			abiVariants["_embind_register_bool"] = "with size argument"
 			b.NewFunctionBuilder().
 				WithName("_embind_register_bool").
 				WithParameterNames("rawType", "name", "size", "trueValue", "falseValue").
 				WithGoModuleFunction(internal.RegisterBool(true), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
 				Export("_embind_register_bool")
 		} else {
+			abiVariants["_embind_register_bool"] = "without size argument"
 			b.NewFunctionBuilder().
 				WithName("_embind_register_bool").
 				WithParameterNames("rawType", "name", "trueValue", "falseValue").
@@ -139,12 +242,14 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		// Since Emscripten 3.1.53, the name parameter has been removed.
 		emvalHasNameArgument := len(importedEmbindRegisterEmval.ParamTypes()) == 2
 		if emvalHasNameArgument {
+			abiVariants["_embind_register_emval"] = "with name argument"
 			b.NewFunctionBuilder().
 				WithName("_embind_register_emval").
 				WithParameterNames("rawType", "name").
 				WithGoModuleFunction(internal.RegisterEmval(true), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
 				Export("_embind_register_emval")
 		} else {
+			abiVariants["_embind_register_emval"] = "without name argument"
 			b.NewFunctionBuilder().
 				WithName("_embind_register_emval").
 				WithParameterNames("rawType").
@@ -153,6 +258,18 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		}
 	}
 
+	b.NewFunctionBuilder().
+		WithName("_embind_register_user_type").
+		WithParameterNames("rawType", "name").
+		WithGoModuleFunction(internal.RegisterUserType, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
+		Export("_embind_register_user_type")
+
+	b.NewFunctionBuilder().
+		WithName("_embind_register_optional").
+		WithParameterNames("rawOptionalType", "rawType").
+		WithGoModuleFunction(internal.RegisterOptional, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
+		Export("_embind_register_optional")
+
 	b.NewFunctionBuilder().
 		WithName("_embind_register_constant").
 		WithParameterNames("rawType", "type", "value").
@@ -249,6 +366,7 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		// The new param is called "kind".
 		emvalGetMethodCallerHasKindParam := len(importedEmvalGetMethodCaller.ParamTypes()) == 3
 		if emvalGetMethodCallerHasKindParam {
+			abiVariants["_emval_get_method_caller"] = "with kind argument"
 			b.NewFunctionBuilder().
 				WithName("_emval_get_method_caller").
 				WithParameterNames("argCount", "argTypes", "kind").
@@ -256,6 +374,7 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 				WithGoModuleFunction(internal.EmvalGetMethodCaller(true), []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).
 				Export("_emval_get_method_caller")
 		} else {
+			abiVariants["_emval_get_method_caller"] = "without kind argument"
 			b.NewFunctionBuilder().
 				WithName("_emval_get_method_caller").
 				WithParameterNames("argCount", "argTypes").
@@ -270,6 +389,7 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		// Since Emscripten 3.1.48, _emval_call has a F64 return.
 		importedEmvalCallHasF64Return := importedEmvalCall.ResultTypes()[0] == api.ValueTypeF64
 		if importedEmvalCallHasF64Return {
+			abiVariants["_emval_call"] = "f64 result"
 			b.NewFunctionBuilder().
 				WithName("_emval_call").
 				WithParameterNames("handle", "argCount", "argTypes", "argv").
@@ -278,6 +398,7 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 				Export("_emval_call")
 
 		} else {
+			abiVariants["_emval_call"] = "i32 result"
 			b.NewFunctionBuilder().
 				WithName("_emval_call").
 				WithParameterNames("handle", "argCount", "argTypes", "argv").
@@ -288,6 +409,49 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		}
 	}
 
+	// Newer Emscripten versions replaced _emval_get_method_caller, _emval_call
+	// and _emval_call_method with _emval_create_invoker and _emval_invoke.
+	importedEmvalCreateInvoker := e.GetImportedFunction("_emval_create_invoker")
+	if importedEmvalCreateInvoker != nil {
+		abiVariants["_emval_create_invoker"] = "invoker"
+		b.NewFunctionBuilder().
+			WithName("_emval_create_invoker").
+			WithParameterNames("argCount", "argTypes", "kind").
+			WithResultNames("id").
+			WithGoModuleFunction(internal.EmvalCreateInvoker, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).
+			Export("_emval_create_invoker")
+	}
+
+	importedEmvalInvoke := e.GetImportedFunction("_emval_invoke")
+	if importedEmvalInvoke != nil {
+		abiVariants["_emval_invoke"] = "invoker"
+		b.NewFunctionBuilder().
+			WithName("_emval_invoke").
+			WithParameterNames("caller", "handle", "methodName", "destructorsRef", "args").
+			WithResultNames("value").
+			WithGoModuleFunction(internal.EmvalInvoke, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeF64}).
+			Export("_emval_invoke")
+	}
+
+	b.NewFunctionBuilder().
+		WithName("_emval_from_current_cxa_exception").
+		WithResultNames("handle").
+		WithGoModuleFunction(internal.EmvalFromCurrentCxaException, []api.ValueType{}, []api.ValueType{api.ValueTypeI32}).
+		Export("_emval_from_current_cxa_exception")
+
+	b.NewFunctionBuilder().
+		WithName("_emval_coro_suspend").
+		WithParameterNames("promiseHandle", "awaiterPtr").
+		WithGoModuleFunction(internal.EmvalCoroSuspend, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{}).
+		Export("_emval_coro_suspend")
+
+	b.NewFunctionBuilder().
+		WithName("_emval_coro_make_promise").
+		WithParameterNames("resolveHandlePtr", "rejectHandlePtr").
+		WithResultNames("handle").
+		WithGoModuleFunction(internal.EmvalCoroMakePromise, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).
+		Export("_emval_coro_make_promise")
+
 	b.NewFunctionBuilder().
 		WithName("_emval_call_method").
 		WithParameterNames("caller", "id", "methodName", "destructorsRef", "args").
@@ -632,5 +796,27 @@ func (e functionExporter) ExportFunctions(b wazero.HostModuleBuilder) error {
 		WithGoModuleFunction(internal.EmvalIterNext, []api.ValueType{api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32}).
 		Export("_emval_iter_next")
 
+	// Validate that all Embind imports of the module match the exported host
+	// functions, a mismatch means that the module has been compiled with an
+	// Emscripten version that we don't support.
+	importedFunctions := e.guest.ImportedFunctions()
+	for i := range importedFunctions {
+		module, importName, _ := importedFunctions[i].Import()
+		if module != "env" || !(strings.HasPrefix(importName, "_embind_") || strings.HasPrefix(importName, "_emval_")) {
+			continue
+		}
+
+		importSignature := formatSignature(importedFunctions[i].ParamTypes(), importedFunctions[i].ResultTypes())
+		if b.signatures[importName] != importSignature {
+			return UnsupportedEmscriptenVersionError{
+				Name:               importName,
+				Signature:          importSignature,
+				SupportedSignature: b.signatures[importName],
+			}
+		}
+	}
+
+	e.engine.abiVariants = abiVariants
+
 	return nil
 }